@@ -0,0 +1,58 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// asciiRamp runs from dark to light, for light text on a dark terminal
+const asciiRamp = " .:-=+*#%@"
+
+// Width used for ASCII art when the terminal width is unknown
+const defaultTerminalWidth = 80
+
+// terminalWidth reads the width from $COLUMNS, falling back to 80
+func terminalWidth() int {
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	return defaultTerminalWidth
+}
+
+// asciiArt renders an image as text at most maxCols characters wide. Each
+// character covers a cell twice as tall as it is wide, matching the shape of
+// terminal characters, and shows the cell's mean luminance.
+func asciiArt(img image.Image, maxCols int) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 || maxCols < 1 {
+		return ""
+	}
+
+	cols := min(width, maxCols)
+	rows := max(1, height*cols/width/2)
+
+	var sb strings.Builder
+	sb.Grow((cols + 1) * rows)
+	for r := 0; r < rows; r++ {
+		y0, y1 := r*height/rows, (r+1)*height/rows
+		for c := 0; c < cols; c++ {
+			x0, x1 := c*width/cols, (c+1)*width/cols
+
+			var sum, n int
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					gray := color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+					sum += int(gray.Y)
+					n++
+				}
+			}
+			sb.WriteByte(asciiRamp[sum/n*len(asciiRamp)/256])
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
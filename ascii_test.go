@@ -0,0 +1,48 @@
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+// grayRamp builds a width x height image whose pixel at column x is step*x
+func grayRamp(width, height, step int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Pix[y*img.Stride+x] = uint8(step * x)
+		}
+	}
+	return img
+}
+
+func TestAsciiArt(t *testing.T) {
+	tests := []struct {
+		name    string
+		img     image.Image
+		maxCols int
+		want    string
+	}{
+		{"one character per pixel", grayRamp(4, 4, 85), 80, " -*@\n -*@\n"},
+		{"downsampled to width", grayRamp(8, 4, 36), 4, " -*@\n"},
+		{"black", image.NewGray(image.Rect(0, 0, 3, 2)), 80, "   \n"},
+		{"empty image", image.NewGray(image.Rect(0, 0, 0, 0)), 80, ""},
+		{"no columns", grayRamp(4, 4, 85), 0, ""},
+	}
+	for _, tt := range tests {
+		if got := asciiArt(tt.img, tt.maxCols); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTerminalWidth(t *testing.T) {
+	t.Setenv("COLUMNS", "120")
+	if got := terminalWidth(); got != 120 {
+		t.Errorf("COLUMNS=120: got %d", got)
+	}
+	t.Setenv("COLUMNS", "wide")
+	if got := terminalWidth(); got != defaultTerminalWidth {
+		t.Errorf("COLUMNS=wide: got %d, want %d", got, defaultTerminalWidth)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// MockBackend keeps shares in memory, in the backend's stored encoding
+type MockBackend struct {
+	mu     sync.Mutex
+	shares map[string]string
+}
+
+func NewMockBackend() *MockBackend {
+	return &MockBackend{shares: make(map[string]string)}
+}
+
+func (b *MockBackend) Store(label string, share Point) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.shares[label] = encodeStoredShare(share)
+	return nil
+}
+
+func (b *MockBackend) Load(label string) (Point, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	encoded, ok := b.shares[label]
+	if !ok {
+		return Point{}, fmt.Errorf("%w: %q", ErrShareNotFound, label)
+	}
+	return DecodeShareHex(encoded)
+}
+
+func (b *MockBackend) Delete(label string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.shares[label]; !ok {
+		return fmt.Errorf("%w: %q", ErrShareNotFound, label)
+	}
+	delete(b.shares, label)
+	return nil
+}
+
+func TestBackendRoundTrip(t *testing.T) {
+	secret := big.NewInt(8675309)
+	for name, backend := range map[string]Backend{
+		"mock": NewMockBackend(),
+		"file": FileBackend{Dir: t.TempDir()},
+	} {
+		sss := newTestSharing(t, 3, 5).WithBackend(backend)
+		if err := sss.StoreShares("db-password", sss.GenerateShares(secret)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		shares, err := sss.LoadShares("db-password", []int{2, 4, 5})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := sss.ReconstructSecret(shares); got.Cmp(secret) != 0 {
+			t.Fatalf("%s: reconstructed %s, want %s", name, got, secret)
+		}
+
+		if err := backend.Delete(shareLabel("db-password", big.NewInt(4))); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, err := sss.LoadShares("db-password", []int{2, 4, 5}); !errors.Is(err, ErrShareNotFound) {
+			t.Fatalf("%s: deleted share: got %v, want ErrShareNotFound", name, err)
+		}
+		if err := backend.Delete(shareLabel("db-password", big.NewInt(4))); !errors.Is(err, ErrShareNotFound) {
+			t.Fatalf("%s: deleting twice: got %v, want ErrShareNotFound", name, err)
+		}
+		if _, err := sss.LoadShares("other", []int{1}); !errors.Is(err, ErrShareNotFound) {
+			t.Fatalf("%s: unknown label: got %v, want ErrShareNotFound", name, err)
+		}
+	}
+}
+
+func TestFileBackendLabelStaysInDir(t *testing.T) {
+	b := FileBackend{Dir: t.TempDir()}
+	if got, want := b.path("../escape/1"), b.Dir; filepath.Dir(got) != want {
+		t.Fatalf("path %q is outside %q", got, want)
+	}
+}
+
+func TestFileBackendLargeField(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if err := sss.SetPrime(Prime127); err != nil {
+		t.Fatal(err)
+	}
+	sss.WithBackend(FileBackend{Dir: t.TempDir()})
+	secret := new(big.Int).Sub(Prime127, big.NewInt(2))
+	if err := sss.StoreShares("vault", sss.GenerateShares(secret)); err != nil {
+		t.Fatal(err)
+	}
+
+	shares, err := sss.LoadShares("vault", []int{1, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sss.ReconstructSecret(shares); got.Cmp(secret) != 0 {
+		t.Fatalf("reconstructed %s, want %s", got, secret)
+	}
+
+	// The same shares are out of range for the default field
+	small := newTestSharing(t, 2, 3).WithBackend(sss.backend)
+	if _, err := small.LoadShares("vault", []int{1, 3}); !errors.Is(err, ErrInvalidShare) {
+		t.Fatalf("Prime127 shares loaded over Prime31: got %v, want ErrInvalidShare", err)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrTooManyErrors is returned when corrupted shares exceed the correction bound
+var ErrTooManyErrors = errors.New("too many corrupted shares to correct")
+
+// ReconstructWithErrorCorrection recovers the secret even when up to
+// maxErrors of the shares are wrong, using the Berlekamp–Welch algorithm.
+// It needs at least threshold+2*maxErrors shares, all over the field of the
+// given prime.
+func ReconstructWithErrorCorrection(points []Point, threshold, maxErrors int, prime *big.Int) (*big.Int, error) {
+	if threshold < 1 || maxErrors < 0 {
+		return nil, errors.New("threshold must be positive and maxErrors non-negative")
+	}
+	n := len(points)
+	if n < threshold+2*maxErrors {
+		return nil, fmt.Errorf("have %d shares, need %d to correct %d errors", n, threshold+2*maxErrors, maxErrors)
+	}
+
+	e := maxErrors
+	qTerms := threshold + e
+
+	// Unknowns are q_0..q_{k+e-1} followed by e_0..e_{e-1}; E(x) is monic of
+	// degree e. Each share gives Q(x_i) - y_i*(E(x_i) - x_i^e) = y_i*x_i^e.
+	rows := make([][]*big.Int, n)
+	for i, p := range points {
+		x := new(big.Int).Mod(p.X, prime)
+		y := new(big.Int).Mod(p.Y, prime)
+
+		row := make([]*big.Int, qTerms+e+1)
+		power := big.NewInt(1)
+		for j := 0; j < qTerms+e; j++ {
+			if j < qTerms {
+				row[j] = new(big.Int).Set(power)
+			}
+			if j < e {
+				term := new(big.Int).Mul(y, power)
+				row[qTerms+j] = term.Neg(term).Mod(term, prime)
+			}
+			if j == e {
+				// power is x^e here
+				row[qTerms+e] = new(big.Int).Mul(y, power)
+				row[qTerms+e].Mod(row[qTerms+e], prime)
+			}
+			power = new(big.Int).Mul(power, x)
+			power.Mod(power, prime)
+		}
+		rows[i] = row
+	}
+
+	solution, err := solveModular(rows, qTerms+e, prime)
+	if err != nil {
+		return nil, err
+	}
+
+	q := solution[:qTerms]
+	locator := append(append([]*big.Int{}, solution[qTerms:]...), big.NewInt(1))
+
+	poly, remainder := polyDivMod(q, locator, prime)
+	for _, c := range remainder {
+		if c.Sign() != 0 {
+			return nil, ErrTooManyErrors
+		}
+	}
+
+	// The recovered polynomial must agree with all but at most maxErrors shares
+	mismatches := 0
+	for _, p := range points {
+		if evaluateCoefficients(poly, p.X, prime).Cmp(new(big.Int).Mod(p.Y, prime)) != 0 {
+			mismatches++
+		}
+	}
+	if mismatches > maxErrors {
+		return nil, ErrTooManyErrors
+	}
+
+	if len(poly) == 0 {
+		return big.NewInt(0), nil
+	}
+	return new(big.Int).Set(poly[0]), nil
+}
+
+// solveModular solves an augmented linear system over GF(prime) by Gaussian
+// elimination. Free variables are set to zero.
+func solveModular(rows [][]*big.Int, unknowns int, prime *big.Int) ([]*big.Int, error) {
+	pivotCols := make([]int, 0, unknowns)
+	r := 0
+
+	for col := 0; col < unknowns && r < len(rows); col++ {
+		pivot := -1
+		for i := r; i < len(rows); i++ {
+			if rows[i][col].Sign() != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		rows[r], rows[pivot] = rows[pivot], rows[r]
+
+		// Normalise the pivot row
+		inv := modInverse(rows[r][col], prime)
+		for j := col; j <= unknowns; j++ {
+			rows[r][j].Mul(rows[r][j], inv).Mod(rows[r][j], prime)
+		}
+
+		// Eliminate the column from every other row
+		for i := range rows {
+			if i == r || rows[i][col].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Int).Set(rows[i][col])
+			for j := col; j <= unknowns; j++ {
+				term := new(big.Int).Mul(factor, rows[r][j])
+				rows[i][j].Sub(rows[i][j], term).Mod(rows[i][j], prime)
+			}
+		}
+
+		pivotCols = append(pivotCols, col)
+		r++
+	}
+
+	// A remaining row of the form 0 = c with c != 0 means no solution
+	for i := r; i < len(rows); i++ {
+		if rows[i][unknowns].Sign() != 0 {
+			return nil, ErrTooManyErrors
+		}
+	}
+
+	solution := make([]*big.Int, unknowns)
+	for i := range solution {
+		solution[i] = big.NewInt(0)
+	}
+	for i, col := range pivotCols {
+		solution[col] = new(big.Int).Set(rows[i][unknowns])
+	}
+
+	return solution, nil
+}
+
+// polyDivMod divides polynomials given as ascending coefficients over GF(prime).
+// The divisor must have a non-zero leading coefficient.
+func polyDivMod(dividend, divisor []*big.Int, prime *big.Int) ([]*big.Int, []*big.Int) {
+	remainder := make([]*big.Int, len(dividend))
+	for i, c := range dividend {
+		remainder[i] = new(big.Int).Mod(c, prime)
+	}
+
+	degree := len(divisor) - 1
+	if len(dividend) <= degree {
+		return nil, remainder
+	}
+
+	quotient := make([]*big.Int, len(dividend)-degree)
+	leadInv := modInverse(divisor[degree], prime)
+
+	for i := len(dividend) - 1; i >= degree; i-- {
+		coeff := new(big.Int).Mul(remainder[i], leadInv)
+		coeff.Mod(coeff, prime)
+		quotient[i-degree] = coeff
+
+		for j := 0; j <= degree; j++ {
+			term := new(big.Int).Mul(coeff, divisor[j])
+			remainder[i-degree+j].Sub(remainder[i-degree+j], term).Mod(remainder[i-degree+j], prime)
+		}
+	}
+
+	return quotient, remainder[:degree]
+}
+
+// evaluateCoefficients evaluates ascending coefficients at x using Horner's scheme
+func evaluateCoefficients(coefficients []*big.Int, x, prime *big.Int) *big.Int {
+	result := big.NewInt(0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result.Mul(result, x)
+		result.Add(result, coefficients[i])
+		result.Mod(result, prime)
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestReconstructWithErrorCorrectionLargeField(t *testing.T) {
+	sss := newTestSharing(t, 3, 7)
+	if err := sss.SetPrime(Prime61); err != nil {
+		t.Fatal(err)
+	}
+	secret := big.NewInt(1_000_000_000_000)
+	shares := sss.GenerateShares(secret)
+	// Corrupt two shares; 3 + 2*2 = 7 shares can correct them
+	shares[1].Y = new(big.Int).Add(shares[1].Y, big.NewInt(1))
+	shares[4].Y = big.NewInt(5)
+
+	got, err := ReconstructWithErrorCorrection(shares, 3, 2, sss.prime)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("ReconstructWithErrorCorrection = %v, %v; want %s", got, err, secret)
+	}
+}
+
+func TestReconstructWithErrorCorrection(t *testing.T) {
+	sss := newTestSharing(t, 3, 9)
+	secret := big.NewInt(123456)
+	for e := 0; e <= 3; e++ {
+		shares := sss.GenerateShares(secret)
+		for i := range e {
+			shares[2*i].Y = new(big.Int).Add(shares[2*i].Y, big.NewInt(int64(i+1)))
+		}
+		got, err := ReconstructWithErrorCorrection(shares, 3, 3, sss.prime)
+		if err != nil || got.Cmp(secret) != 0 {
+			t.Errorf("%d corrupted shares: got %v, %v; want %s", e, got, err, secret)
+		}
+	}
+}
+
+func TestReconstructWithErrorCorrectionTooManyErrors(t *testing.T) {
+	sss := newTestSharing(t, 3, 7)
+	shares := sss.GenerateShares(big.NewInt(123456))
+	// Three wrong shares out of seven exceed the bound of (7-3)/2 = 2
+	for i := range 3 {
+		shares[i].Y = big.NewInt(int64(1000 + i))
+	}
+	if got, err := ReconstructWithErrorCorrection(shares, 3, 2, sss.prime); !errors.Is(err, ErrTooManyErrors) {
+		t.Fatalf("got %v, %v; want ErrTooManyErrors", got, err)
+	}
+	if _, err := ReconstructWithErrorCorrection(shares[:6], 3, 2, sss.prime); err == nil {
+		t.Fatal("accepted fewer than threshold+2*maxErrors shares")
+	}
+}
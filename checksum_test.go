@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChecksummedSharesCorruption(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	secret := big.NewInt(8675309)
+	shares := sss.ShareWithChecksum(secret)
+	path := filepath.Join(t.TempDir(), "checked.txt")
+	if err := SaveChecksummedShares(shares, path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, invalid, err := LoadChecksummedShares(path)
+	if err != nil || len(invalid) != 0 {
+		t.Fatalf("clean file: invalid %v, err %v", invalid, err)
+	}
+	for i, share := range loaded {
+		if share.X.Cmp(shares[i].X) != 0 || share.Y.Cmp(shares[i].Y) != 0 || share.CRC != shares[i].CRC {
+			t.Fatalf("share %d: got %v, want %v", i, share, shares[i])
+		}
+	}
+
+	// Flip the last digit of share 2's y-coordinate; line 0 is the count
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(data), "\n")
+	fields := strings.Fields(lines[3])
+	y := []byte(fields[1])
+	y[len(y)-1] = '0' + (y[len(y)-1]-'0'+1)%10
+	fields[1] = string(y)
+	lines[3] = strings.Join(fields, " ")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, invalid, err = LoadChecksummedShares(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(invalid, []int{2}) {
+		t.Fatalf("invalid indices %v, want [2]", invalid)
+	}
+	var good []Point
+	for i, share := range loaded {
+		if !slices.Contains(invalid, i) {
+			good = append(good, share.Point)
+		}
+	}
+	if got := sss.ReconstructSecret(good); got.Cmp(secret) != 0 {
+		t.Fatalf("remaining shares reconstruct to %s, want %s", got, secret)
+	}
+}
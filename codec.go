@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"math/big"
+	"time"
+)
+
+// ErrUnsupportedCodecType is returned for values CodecV1 cannot encode or decode into
+var ErrUnsupportedCodecType = errors.New("unsupported codec type")
+
+// Codec is the shape expected by frameworks with pluggable serialization,
+// such as memberlist or custom gRPC codecs
+type Codec interface {
+	Encode(v interface{}) ([]byte, error)
+	Decode(data []byte, v interface{}) error
+}
+
+// Leading bytes of every CodecV1 message: the format version, then the kind
+// of value that follows as a protobuf message from proto/shares.proto
+const (
+	codecVersion1 = 1
+
+	codecKindShares   = 1 // ShareFile
+	codecKindMetadata = 2 // ShareMetadata
+)
+
+// CodecV1 encodes [][]Point and ShareMetadata values. The format is fixed:
+// later versions get a new type and version byte rather than changing it.
+type CodecV1 struct{}
+
+var _ Codec = CodecV1{}
+
+// Encode serializes a [][]Point or ShareMetadata, or a pointer to either
+func (CodecV1) Encode(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case [][]Point:
+		return append([]byte{codecVersion1, codecKindShares}, MarshalSharesProto(v)...), nil
+	case *[][]Point:
+		return CodecV1{}.Encode(*v)
+	case ShareMetadata:
+		return append([]byte{codecVersion1, codecKindMetadata}, marshalMetadataProto(v)...), nil
+	case *ShareMetadata:
+		return CodecV1{}.Encode(*v)
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedCodecType, v)
+}
+
+// Decode deserializes data produced by Encode into a *[][]Point or *ShareMetadata
+func (CodecV1) Decode(data []byte, v interface{}) error {
+	if len(data) < 2 || data[0] != codecVersion1 {
+		return fmt.Errorf("%w: not a version 1 codec message", ErrInvalidProto)
+	}
+	kind, body := data[1], data[2:]
+
+	switch v := v.(type) {
+	case *[][]Point:
+		if kind != codecKindShares {
+			return fmt.Errorf("%w: message does not hold shares", ErrInvalidProto)
+		}
+		allShares, err := UnmarshalSharesProto(body)
+		if err != nil {
+			return err
+		}
+		*v = allShares
+		return nil
+	case *ShareMetadata:
+		if kind != codecKindMetadata {
+			return fmt.Errorf("%w: message does not hold metadata", ErrInvalidProto)
+		}
+		meta, err := unmarshalMetadataProto(body)
+		if err != nil {
+			return err
+		}
+		*v = meta
+		return nil
+	}
+	return fmt.Errorf("%w: %T", ErrUnsupportedCodecType, v)
+}
+
+// appendProtoVarint appends a varint field, skipping zero values as proto3 does
+func appendProtoVarint(buf []byte, field int, value int64) []byte {
+	if value == 0 {
+		return buf
+	}
+	buf = binary.AppendUvarint(buf, uint64(field<<3|protoVarint))
+	return binary.AppendUvarint(buf, uint64(value))
+}
+
+// marshalMetadataProto encodes a ShareMetadata message
+func marshalMetadataProto(meta ShareMetadata) []byte {
+	var out []byte
+	if meta.Digest != nil {
+		out = appendProtoBytes(out, 1, meta.Digest.Bytes())
+	}
+	if meta.Nonce != nil {
+		out = appendProtoBytes(out, 2, meta.Nonce)
+	}
+	if meta.Description != "" {
+		out = appendProtoBytes(out, 3, []byte(meta.Description))
+	}
+	out = appendProtoVarint(out, 4, int64(meta.Threshold))
+	out = appendProtoVarint(out, 5, int64(meta.XOffset))
+	out = appendProtoVarint(out, 6, int64(meta.Depth))
+	if !meta.Created.IsZero() {
+		out = appendProtoVarint(out, 7, meta.Created.UnixNano())
+	}
+	if meta.Version != "" {
+		out = appendProtoBytes(out, 8, []byte(meta.Version))
+	}
+	if meta.Palette != nil {
+		palette := make([]byte, 0, 4*len(meta.Palette))
+		for _, c := range meta.Palette {
+			n := color.NRGBAModel.Convert(c).(color.NRGBA)
+			palette = append(palette, n.R, n.G, n.B, n.A)
+		}
+		out = appendProtoBytes(out, 9, palette)
+	}
+	if meta.Format != "" {
+		out = appendProtoBytes(out, 10, []byte(meta.Format))
+	}
+	if meta.Scheme != SchemePolynomial {
+		out = appendProtoBytes(out, 11, []byte(meta.Scheme.String()))
+	}
+	if meta.Prime != nil {
+		out = appendProtoBytes(out, 12, meta.Prime.Bytes())
+	}
+	return out
+}
+
+// unmarshalMetadataProto decodes a ShareMetadata message
+func unmarshalMetadataProto(data []byte) (ShareMetadata, error) {
+	var meta ShareMetadata
+	err := walkProto(data, func(field int, value []byte) error {
+		var n int64
+		switch field {
+		case 4, 5, 6, 7:
+			u, size := binary.Uvarint(value)
+			if size <= 0 {
+				return fmt.Errorf("%w: bad varint in field %d", ErrInvalidProto, field)
+			}
+			n = int64(u)
+		}
+
+		switch field {
+		case 1:
+			meta.Digest = new(big.Int).SetBytes(value)
+		case 2:
+			meta.Nonce = append([]byte{}, value...)
+		case 3:
+			meta.Description = string(value)
+		case 4:
+			meta.Threshold = int(n)
+		case 5:
+			meta.XOffset = int(n)
+		case 6:
+			meta.Depth = int(n)
+		case 7:
+			meta.Created = time.Unix(0, n).UTC()
+		case 8:
+			meta.Version = string(value)
+		case 9:
+			if len(value)%4 != 0 {
+				return fmt.Errorf("%w: palette of %d bytes", ErrInvalidProto, len(value))
+			}
+			meta.Palette = make(color.Palette, 0, len(value)/4)
+			for i := 0; i < len(value); i += 4 {
+				meta.Palette = append(meta.Palette, color.NRGBA{R: value[i], G: value[i+1], B: value[i+2], A: value[i+3]})
+			}
+		case 10:
+			meta.Format = string(value)
+		case 11:
+			scheme, err := ParseScheme(string(value))
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidProto, err)
+			}
+			meta.Scheme = scheme
+		case 12:
+			prime := new(big.Int).SetBytes(value)
+			if !prime.ProbablyPrime(32) {
+				return fmt.Errorf("%w: field %s is not prime", ErrInvalidProto, prime)
+			}
+			meta.Prime = prime
+		}
+		return nil
+	})
+	return meta, err
+}
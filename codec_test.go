@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCodecV1ShareTypes(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	raw, err := sss.ShareArbitraryBytes([]byte{0, 1, 254, 255})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, allShares := range map[string][][]Point{
+		"text":  mustShareText(t, sss, "codec"),
+		"image": sss.sharePixels([]uint8{0, 128, 255, 64}),
+		"raw":   raw,
+	} {
+		var codec Codec = CodecV1{}
+		data, err := codec.Encode(allShares)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		viaPointer, err := codec.Encode(&allShares)
+		if err != nil || !bytes.Equal(viaPointer, data) {
+			t.Fatalf("%s: encoding through a pointer differs: %v", name, err)
+		}
+
+		var decoded [][]Point
+		if err := codec.Decode(data, &decoded); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(sharesText(decoded), sharesText(allShares)) {
+			t.Fatalf("%s: decoded shares differ", name)
+		}
+	}
+}
+
+func TestCodecV1Metadata(t *testing.T) {
+	meta := ShareMetadata{
+		Digest:      big.NewInt(0xabcdef),
+		Nonce:       []byte{1, 2, 3},
+		Description: "vault",
+		Threshold:   3,
+		XOffset:     10,
+		Depth:       16,
+		Palette:     color.Palette{color.NRGBA{R: 1, G: 2, B: 3, A: 255}},
+		Format:      "png",
+		Scheme:      SchemeXOR,
+		Prime:       Prime61,
+		Created:     time.Date(2026, 5, 6, 7, 8, 9, 10, time.UTC),
+		Version:     Version,
+	}
+	data, err := CodecV1{}.Encode(&meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ShareMetadata
+	if err := (CodecV1{}).Decode(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, meta) {
+		t.Fatalf("decoded %+v\nwant %+v", decoded, meta)
+	}
+
+	// Kinds are not interchangeable
+	var allShares [][]Point
+	if err := (CodecV1{}).Decode(data, &allShares); !errors.Is(err, ErrInvalidProto) {
+		t.Fatalf("metadata into shares: got %v, want ErrInvalidProto", err)
+	}
+}
+
+// The wire format must never change; these bytes were produced by the
+// first release of CodecV1
+func TestCodecV1WireVectors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		value interface{}
+		wire  []byte
+	}{
+		{
+			"shares",
+			[][]Point{{{X: big.NewInt(1), Y: big.NewInt(0x0102)}}},
+			[]byte{0x01, 0x01, 0x0a, 0x08, 0x0a, 0x06, 0x08, 0x01, 0x12, 0x02, 0x01, 0x02},
+		},
+		{
+			"metadata",
+			ShareMetadata{Description: "hi", Threshold: 2, Prime: big.NewInt(7)},
+			[]byte{0x01, 0x02, 0x1a, 0x02, 'h', 'i', 0x20, 0x02, 0x62, 0x01, 0x07},
+		},
+	} {
+		got, err := CodecV1{}.Encode(tc.value)
+		if err != nil || !bytes.Equal(got, tc.wire) {
+			t.Errorf("%s: Encode = % x, %v; want % x", tc.name, got, err, tc.wire)
+		}
+	}
+}
+
+func TestCodecV1Unsupported(t *testing.T) {
+	if _, err := (CodecV1{}).Encode("text"); !errors.Is(err, ErrUnsupportedCodecType) {
+		t.Errorf("Encode(string): got %v, want ErrUnsupportedCodecType", err)
+	}
+	var s string
+	if err := (CodecV1{}).Decode([]byte{1, 1}, &s); !errors.Is(err, ErrUnsupportedCodecType) {
+		t.Errorf("Decode into *string: got %v, want ErrUnsupportedCodecType", err)
+	}
+	var allShares [][]Point
+	for _, data := range [][]byte{nil, {1}, {2, 1}} {
+		if err := (CodecV1{}).Decode(data, &allShares); !errors.Is(err, ErrInvalidProto) {
+			t.Errorf("Decode(% x): got %v, want ErrInvalidProto", data, err)
+		}
+	}
+}
+
+// sharesText renders shares for comparison, since equal big.Ints need not
+// be DeepEqual
+func sharesText(allShares [][]Point) [][]string {
+	out := make([][]string, len(allShares))
+	for i, shares := range allShares {
+		for _, p := range shares {
+			out[i] = append(out[i], p.String())
+		}
+	}
+	return out
+}
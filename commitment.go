@@ -0,0 +1,136 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// ErrFieldTooSmall is returned when commitments are asked for over a field
+// too small for them to hide or bind anything
+var ErrFieldTooSmall = errors.New("field is too small for commitments")
+
+// Smallest field, in bits, that commitments are built over. Below this the
+// committed values, or the group's discrete logs, can be found by search.
+const minCommitmentFieldBits = 256
+
+// CommitmentScheme binds a dealer to a value without revealing it up front.
+// randomness must be fresh, secret and at least as large as the field for
+// each commitment: a commitment is only as hidden as its randomness, so
+// Commit panics on nil randomness and Open rejects it.
+type CommitmentScheme interface {
+	Commit(secret *big.Int, randomness *big.Int) *big.Int
+	Open(commitment, secret, randomness *big.Int) bool
+}
+
+// Bit length of the modulus of the Pedersen groups
+const pedersenModulusBits = 2048
+
+// PedersenCommitment commits to v as G^v * H^r mod P, where G and H generate
+// the subgroup of prime order Q and nobody knows log_G(H). Commitments hide
+// v perfectly and bind as long as discrete logs in the group are hard.
+type PedersenCommitment struct {
+	P, Q, G, H *big.Int
+}
+
+// Groups found so far, by order; each takes a while to find
+var pedersenGroups sync.Map // Q.String() -> *PedersenCommitment
+
+// NewPedersenCommitment returns the Pedersen group whose order is the field
+// prime, which must have at least 256 bits, such as Prime521. The group is
+// found deterministically on first use, so every caller agrees on it.
+func NewPedersenCommitment(prime *big.Int) (*PedersenCommitment, error) {
+	if err := checkCommitmentField(prime); err != nil {
+		return nil, err
+	}
+	if group, ok := pedersenGroups.Load(prime.String()); ok {
+		return group.(*PedersenCommitment), nil
+	}
+	group, _ := pedersenGroups.LoadOrStore(prime.String(), newPedersenGroup(prime, pedersenModulusBits))
+	return group.(*PedersenCommitment), nil
+}
+
+// checkCommitmentField refuses primes too small to commit over
+func checkCommitmentField(prime *big.Int) error {
+	if prime == nil || !prime.ProbablyPrime(32) {
+		return fmt.Errorf("%w: %v", ErrInvalidPrime, prime)
+	}
+	if prime.BitLen() < minCommitmentFieldBits {
+		return fmt.Errorf("%w: %d-bit field, need at least %d bits", ErrFieldTooSmall, prime.BitLen(), minCommitmentFieldBits)
+	}
+	return nil
+}
+
+// newPedersenGroup finds the first prime P = k*q + 1 of the given size and
+// derives two generators of the order-q subgroup, H from a hash so that its
+// discrete log relative to G is unknown
+func newPedersenGroup(q *big.Int, bits int) *PedersenCommitment {
+	one := big.NewInt(1)
+	k := new(big.Int).Lsh(one, uint(bits-q.BitLen()))
+	p := new(big.Int)
+	for {
+		p.Mul(k, q).Add(p, one)
+		if p.ProbablyPrime(32) {
+			break
+		}
+		k.Add(k, big.NewInt(2))
+	}
+
+	generator := func(seed *big.Int) *big.Int {
+		for {
+			g := new(big.Int).Exp(seed, k, p)
+			if g.Cmp(one) != 0 {
+				return g
+			}
+			seed.Add(seed, one)
+		}
+	}
+	hSeed := sha256.Sum256([]byte("shamir pedersen generator h"))
+	return &PedersenCommitment{
+		P: p,
+		Q: new(big.Int).Set(q),
+		G: generator(big.NewInt(2)),
+		H: generator(new(big.Int).SetBytes(hSeed[:])),
+	}
+}
+
+// Commit returns G^secret * H^randomness mod P
+func (pc *PedersenCommitment) Commit(secret, randomness *big.Int) *big.Int {
+	if randomness == nil {
+		panic("Pedersen commitment needs randomness")
+	}
+	c := new(big.Int).Exp(pc.G, new(big.Int).Mod(secret, pc.Q), pc.P)
+	hr := new(big.Int).Exp(pc.H, new(big.Int).Mod(randomness, pc.Q), pc.P)
+	return c.Mul(c, hr).Mod(c, pc.P)
+}
+
+// Open reports whether commitment was made to secret with randomness
+func (pc *PedersenCommitment) Open(commitment, secret, randomness *big.Int) bool {
+	return randomness != nil && pc.Commit(secret, randomness).Cmp(commitment) == 0
+}
+
+// HashCommitment commits to a value with SHA-256. It needs no group setup,
+// which makes it handy for tests, but it has none of Pedersen's algebraic
+// structure, so FeldmanVSS can only check its shares one by one.
+type HashCommitment struct{}
+
+// Commit returns SHA-256 over the length-prefixed secret and randomness
+func (HashCommitment) Commit(secret, randomness *big.Int) *big.Int {
+	if randomness == nil {
+		panic("hash commitment needs randomness")
+	}
+	h := sha256.New()
+	for _, v := range []*big.Int{secret, randomness} {
+		b := v.Bytes()
+		h.Write([]byte{byte(len(b) >> 8), byte(len(b))})
+		h.Write(b)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// Open reports whether commitment was made to secret with randomness
+func (hc HashCommitment) Open(commitment, secret, randomness *big.Int) bool {
+	return randomness != nil && hc.Commit(secret, randomness).Cmp(commitment) == 0
+}
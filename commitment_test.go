@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// newLargeFieldSharing builds an instance over Prime521, which commitments need
+func newLargeFieldSharing(tb testing.TB, threshold, numShares int) *ShamirSecretSharing {
+	tb.Helper()
+	sss := newTestSharing(tb, threshold, numShares)
+	if err := sss.SetPrime(Prime521); err != nil {
+		tb.Fatal(err)
+	}
+	return sss
+}
+
+// commitmentSchemes returns one of each scheme over Prime521
+func commitmentSchemes(tb testing.TB) map[string]CommitmentScheme {
+	tb.Helper()
+	pedersen, err := NewPedersenCommitment(Prime521)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return map[string]CommitmentScheme{"pedersen": pedersen, "hash": HashCommitment{}}
+}
+
+func TestCommitmentsRefuseSmallFields(t *testing.T) {
+	for _, prime := range []*big.Int{Prime31, Prime61, Prime127} {
+		if _, err := NewPedersenCommitment(prime); !errors.Is(err, ErrFieldTooSmall) {
+			t.Errorf("NewPedersenCommitment(%d-bit prime): got %v, want ErrFieldTooSmall", prime.BitLen(), err)
+		}
+		sss := newTestSharing(t, 2, 3)
+		if err := sss.SetPrime(prime); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewFeldmanVSS(sss, HashCommitment{}); !errors.Is(err, ErrFieldTooSmall) {
+			t.Errorf("NewFeldmanVSS over a %d-bit prime: got %v, want ErrFieldTooSmall", prime.BitLen(), err)
+		}
+	}
+}
+
+func TestFeldmanVSSRejectsMismatchedGroup(t *testing.T) {
+	group, err := NewPedersenCommitment(Prime521)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sss := newLargeFieldSharing(t, 2, 3)
+	other, _ := new(big.Int).SetString("115792089210356248762697446949407573529996955224135760342422259061068512044369", 10)
+	if err := sss.SetPrime(other); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFeldmanVSS(sss, group); !errors.Is(err, ErrInvalidPrime) {
+		t.Fatalf("group of another order: got %v, want ErrInvalidPrime", err)
+	}
+}
+
+func TestCommitmentOpenNeedsRandomness(t *testing.T) {
+	secret, randomness := big.NewInt(42), big.NewInt(7)
+	for name, scheme := range commitmentSchemes(t) {
+		c := scheme.Commit(secret, randomness)
+		if !scheme.Open(c, secret, randomness) {
+			t.Errorf("%s: commitment does not open", name)
+		}
+		if scheme.Open(c, secret, nil) {
+			t.Errorf("%s: opened with nil randomness", name)
+		}
+		if scheme.Open(c, big.NewInt(43), randomness) {
+			t.Errorf("%s: opened to the wrong secret", name)
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: Commit accepted nil randomness", name)
+				}
+			}()
+			scheme.Commit(secret, nil)
+		}()
+	}
+}
+
+func TestFeldmanVSSCommitmentsHideSecret(t *testing.T) {
+	for name, scheme := range commitmentSchemes(t) {
+		vss, err := NewFeldmanVSS(newLargeFieldSharing(t, 2, 3), scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A small secret dealt twice must not give the same, searchable, commitment
+		_, _, first, err := vss.Deal(big.NewInt(7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, second, err := vss.Deal(big.NewInt(7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first[0].Cmp(second[0]) == 0 {
+			t.Errorf("%s: equal commitments to the same secret", name)
+		}
+	}
+}
+
+func TestFeldmanVSSRoundTrip(t *testing.T) {
+	secret := big.NewInt(123456789)
+	for name, scheme := range commitmentSchemes(t) {
+		vss, err := NewFeldmanVSS(newLargeFieldSharing(t, 3, 5), scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares, blindings, commitments, err := vss.Deal(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, share := range shares {
+			if err := vss.VerifyShare(share, blindings[i], commitments); err != nil {
+				t.Errorf("%s: share %d: %v", name, i, err)
+			}
+		}
+
+		got, err := vss.Reconstruct(shares[2:], blindings[2:], commitments)
+		if err != nil || got.Cmp(secret) != 0 {
+			t.Errorf("%s: Reconstruct = %v, %v; want %s", name, got, err, secret)
+		}
+
+		tampered := Point{X: shares[0].X, Y: new(big.Int).Add(shares[0].Y, big.NewInt(1))}
+		if err := vss.VerifyShare(tampered, blindings[0], commitments); !errors.Is(err, ErrShareCommitmentMismatch) {
+			t.Errorf("%s: tampered share: got %v, want ErrShareCommitmentMismatch", name, err)
+		}
+		if err := vss.VerifyShare(shares[0], blindings[1], commitments); !errors.Is(err, ErrShareCommitmentMismatch) {
+			t.Errorf("%s: wrong blinding value: got %v, want ErrShareCommitmentMismatch", name, err)
+		}
+	}
+}
+
+func BenchmarkCommitmentOpen(b *testing.B) {
+	secret, randomness := big.NewInt(123456789), new(big.Int).Rsh(Prime521, 1)
+	for name, scheme := range commitmentSchemes(b) {
+		c := scheme.Commit(secret, randomness)
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				scheme.Open(c, secret, randomness)
+			}
+		})
+	}
+}
+
+func TestDealerCheats(t *testing.T) {
+	secret := big.NewInt(42424242)
+	for name, scheme := range commitmentSchemes(t) {
+		vss, err := NewFeldmanVSS(newLargeFieldSharing(t, 3, 5), scheme)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares, blindings, commitments, err := vss.Deal(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bad, err := vss.BroadcastVerify(shares, blindings, commitments); err != nil || bad != nil {
+			t.Fatalf("%s: honest dealer: %v, %v", name, bad, err)
+		}
+		if vss.DetectDealerCheat(shares[0], blindings[0], commitments, shares[1], blindings[1], commitments) {
+			t.Fatalf("%s: honest dealer reported as cheating", name)
+		}
+
+		// Participant 2 gets a wrong share; the commitments stay correct
+		cheated := append([]Point(nil), shares...)
+		cheated[1] = Point{X: shares[1].X, Y: new(big.Int).Add(shares[1].Y, big.NewInt(1))}
+		bad, err := vss.BroadcastVerify(cheated, blindings, commitments)
+		if !errors.Is(err, ErrDealerAbort) || len(bad) != 1 || bad[0] != 1 {
+			t.Errorf("%s: wrong share: got %v, %v; want index 1 and ErrDealerAbort", name, bad, err)
+		}
+		if !vss.DetectDealerCheat(shares[0], blindings[0], commitments, cheated[1], blindings[1], commitments) {
+			t.Errorf("%s: participants 1 and 2 did not detect the wrong share", name)
+		}
+
+		// Participants are sent different commitment vectors
+		forked := append([]*big.Int(nil), commitments...)
+		forked[2] = scheme.Commit(cheated[1].Y, blindings[1])
+		if !vss.DetectDealerCheat(shares[0], blindings[0], commitments, cheated[1], blindings[1], forked) {
+			t.Errorf("%s: differing commitment vectors not detected", name)
+		}
+	}
+}
+
+func TestDealerCheatsOffPolynomial(t *testing.T) {
+	// A wrong share with a matching commitment passes every per-share check;
+	// only Pedersen commitments reveal that it is off the dealer's polynomial
+	pedersen := commitmentSchemes(t)["pedersen"]
+	vss, err := NewFeldmanVSS(newLargeFieldSharing(t, 3, 5), pedersen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, blindings, commitments, err := vss.Deal(big.NewInt(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares[4].Y.Add(shares[4].Y, big.NewInt(1))
+	commitments[5] = pedersen.Commit(shares[4].Y, blindings[4])
+
+	if err := vss.VerifyShare(shares[4], blindings[4], commitments); err != nil {
+		t.Fatalf("the altered share should open its own commitment: %v", err)
+	}
+	if _, err := vss.BroadcastVerify(shares, blindings, commitments); !errors.Is(err, ErrDealerAbort) {
+		t.Fatalf("BroadcastVerify: got %v, want ErrDealerAbort", err)
+	}
+	if !vss.DetectDealerCheat(shares[0], blindings[0], commitments, shares[4], blindings[4], commitments) {
+		t.Fatal("DetectDealerCheat missed commitments off the polynomial")
+	}
+}
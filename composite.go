@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ShareImagesComposite shares several same-sized images so that corresponding
+// pixels use the same polynomial coefficients and only the constant term
+// differs. The result is indexed [image][pixel][share]; any threshold of
+// holders can reconstruct every image, and fewer can reconstruct none.
+//
+// The correlation has a cost: a single holder can subtract their shares for
+// two images and learn the difference of the pixel values mod the prime. Use
+// this only when the images' relative contents need not be secret.
+func (sss *ShamirSecretSharing) ShareImagesComposite(imagePaths []string) ([][][]Point, int, int, error) {
+	if len(imagePaths) == 0 {
+		return nil, 0, 0, errors.New("at least one image is required")
+	}
+
+	images := make([][]uint8, len(imagePaths))
+	var width, height int
+	for i, path := range imagePaths {
+		pixels, w, h, err := loadGrayPixels(path, sss.conversion)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("%s: %w", path, err)
+		}
+		if i > 0 && (w != width || h != height) {
+			return nil, 0, 0, fmt.Errorf("%s is %dx%d, expected %dx%d like %s",
+				path, w, h, width, height, imagePaths[0])
+		}
+		images[i], width, height = pixels, w, h
+	}
+
+	allShares := make([][][]Point, len(images))
+	allShares[0] = sss.sharePixels(images[0])
+	for k := 1; k < len(images); k++ {
+		allShares[k] = make([][]Point, len(images[k]))
+		for p, base := range allShares[0] {
+			allShares[k][p] = sss.shiftShares(base, images[0][p], images[k][p])
+		}
+	}
+	return allShares, width, height, nil
+}
+
+// shiftShares turns shares of from into shares of to that reuse the same
+// randomness: the polynomial is moved by to-from, or under SchemeXOR the
+// last XOR mask absorbs the change
+func (sss *ShamirSecretSharing) shiftShares(shares []Point, from, to uint8) []Point {
+	shifted := make([]Point, len(shares))
+	for i, share := range shares {
+		shifted[i] = Point{X: share.X, Y: new(big.Int).Set(share.Y)}
+	}
+
+	if sss.usesXOR() {
+		last := shifted[len(shifted)-1].Y
+		last.Xor(last, big.NewInt(int64(from^to)))
+		return shifted
+	}
+
+	delta := big.NewInt(int64(to) - int64(from))
+	for _, share := range shifted {
+		share.Y.Add(share.Y, delta).Mod(share.Y, sss.prime)
+	}
+	return shifted
+}
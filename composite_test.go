@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"math/big"
+	"testing"
+)
+
+func TestShareImagesComposite(t *testing.T) {
+	var paths []string
+	var originals [][]uint8
+	for k := range 3 {
+		img := image.NewGray(image.Rect(0, 0, 5, 4))
+		for i := range img.Pix {
+			img.Pix[i] = uint8(i*11 + k*70)
+		}
+		paths = append(paths, writeTestPNG(t, img))
+		originals = append(originals, img.Pix)
+	}
+
+	sss := newTestSharing(t, 2, 3)
+	allShares, width, height, err := sss.ShareImagesComposite(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allShares) != 3 || width != 5 || height != 4 {
+		t.Fatalf("got %d images of %dx%d", len(allShares), width, height)
+	}
+
+	for k, shares := range allShares {
+		pixels, err := sss.ReconstructImageBytes(subsetShares(shares, 0, 2), width, height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(pixels, originals[k]) {
+			t.Errorf("image %d differs after reconstruction", k)
+		}
+	}
+
+	// Corresponding pixels share one polynomial up to the constant term, so
+	// every participant's share moves by the same amount: a quorum recovers
+	// all images or none
+	for k := 1; k < 3; k++ {
+		for p := range allShares[0] {
+			want := big.NewInt(int64(originals[k][p]) - int64(originals[0][p]))
+			want.Mod(want, sss.prime)
+			for j := range allShares[0][p] {
+				diff := new(big.Int).Sub(allShares[k][p][j].Y, allShares[0][p][j].Y)
+				if diff.Mod(diff, sss.prime).Cmp(want) != 0 {
+					t.Fatalf("image %d pixel %d share %d: shifted by %s, want %s", k, p, j, diff, want)
+				}
+			}
+		}
+	}
+}
+
+func TestShareImagesCompositeSizeMismatch(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	paths := []string{
+		writeTestPNG(t, image.NewGray(image.Rect(0, 0, 4, 4))),
+		writeTestPNG(t, image.NewGray(image.Rect(0, 0, 4, 5))),
+	}
+	if _, _, _, err := sss.ShareImagesComposite(paths); err == nil {
+		t.Fatal("images of different sizes accepted")
+	}
+	if _, _, _, err := sss.ShareImagesComposite(nil); err == nil {
+		t.Fatal("no images accepted")
+	}
+}
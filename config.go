@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidConfig is returned when a serialized scheme configuration is unusable
+var ErrInvalidConfig = errors.New("invalid scheme configuration")
+
+// ErrMissingEnvVar is returned when a required environment variable is unset
+var ErrMissingEnvVar = errors.New("missing environment variable")
+
+// Environment variables read by NewShamirSecretSharingFromEnv
+const (
+	envThreshold = "SSS_THRESHOLD"
+	envNumShares = "SSS_NUM_SHARES"
+	envPrimeBits = "SSS_PRIME_BITS"
+)
+
+// schemeConfig is the JSON form of a scheme's parameters
+type schemeConfig struct {
+	Threshold int    `json:"threshold"`
+	NumShares int    `json:"num_shares"`
+	Prime     string `json:"prime"`
+	XOffset   int    `json:"x_offset,omitempty"`
+	Scheme    string `json:"scheme,omitempty"` // empty for polynomial shares
+}
+
+// ShareEnvelope bundles a share with the scheme it belongs to, for handing
+// to a remote holder
+type ShareEnvelope struct {
+	Config   []byte
+	Share    Point
+	HolderID string
+}
+
+// SerializeConfig encodes the scheme parameters as JSON
+func (sss *ShamirSecretSharing) SerializeConfig() ([]byte, error) {
+	cfg := schemeConfig{
+		Threshold: sss.threshold,
+		NumShares: sss.numShares,
+		Prime:     sss.prime.String(),
+		XOffset:   sss.xOffset,
+	}
+	if sss.usesXOR() {
+		cfg.Scheme = sss.scheme.String()
+	}
+	return json.Marshal(cfg)
+}
+
+// DeserializeConfig rebuilds a scheme from SerializeConfig output
+func DeserializeConfig(data []byte) (*ShamirSecretSharing, error) {
+	var cfg schemeConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	if cfg.XOffset < 0 {
+		return nil, fmt.Errorf("%w: negative x offset %d", ErrInvalidConfig, cfg.XOffset)
+	}
+	prime, ok := new(big.Int).SetString(cfg.Prime, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid prime %q", ErrInvalidConfig, cfg.Prime)
+	}
+
+	sss, err := NewShamirSecretSharing(cfg.Threshold, cfg.NumShares)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	sss.SetXOffset(cfg.XOffset)
+	if err := sss.SetPrime(prime); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if cfg.Scheme != "" {
+		scheme, err := ParseScheme(cfg.Scheme)
+		if err == nil {
+			err = sss.SetScheme(scheme)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+	return sss, nil
+}
+
+// NewEnvelope packages a share and the scheme configuration for a holder
+func NewEnvelope(sss *ShamirSecretSharing, share Point, holderID string) (ShareEnvelope, error) {
+	config, err := sss.SerializeConfig()
+	if err != nil {
+		return ShareEnvelope{}, err
+	}
+	return ShareEnvelope{Config: config, Share: share, HolderID: holderID}, nil
+}
+
+// OpenEnvelope unpacks an envelope created by NewEnvelope
+func OpenEnvelope(env ShareEnvelope) (*ShamirSecretSharing, Point, string, error) {
+	sss, err := DeserializeConfig(env.Config)
+	if err != nil {
+		return nil, Point{}, "", err
+	}
+	if env.Share.X == nil || env.Share.Y == nil {
+		return nil, Point{}, "", fmt.Errorf("%w: envelope has no share", ErrInvalidConfig)
+	}
+	return sss, env.Share, env.HolderID, nil
+}
+
+// NewShamirSecretSharingFromEnv builds a scheme from SSS_THRESHOLD and
+// SSS_NUM_SHARES. SSS_PRIME_BITS is optional and selects one of the preset
+// fields (31, 61, 127 or 521); without it the scheme uses Prime31.
+func NewShamirSecretSharingFromEnv() (*ShamirSecretSharing, error) {
+	threshold, err := intFromEnv(envThreshold)
+	if err != nil {
+		return nil, err
+	}
+	numShares, err := intFromEnv(envNumShares)
+	if err != nil {
+		return nil, err
+	}
+
+	sss, err := NewShamirSecretSharing(threshold, numShares)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := os.LookupEnv(envPrimeBits); ok {
+		bits, err := intFromEnv(envPrimeBits)
+		if err != nil {
+			return nil, err
+		}
+		prime, ok := fieldPresets[fmt.Sprintf("prime%d", bits)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s=%d is not one of %s",
+				ErrInvalidConfig, envPrimeBits, bits, strings.Join(FieldNames(), ", "))
+		}
+		if err := sss.SetPrime(prime); err != nil {
+			return nil, err
+		}
+	}
+
+	return sss, nil
+}
+
+// intFromEnv reads a required integer environment variable
+func intFromEnv(name string) (int, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return 0, fmt.Errorf("%w: %s", ErrMissingEnvVar, name)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s=%q is not an integer", ErrInvalidConfig, name, value)
+	}
+	return n, nil
+}
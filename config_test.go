@@ -0,0 +1,136 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSerializeConfigRoundTrip(t *testing.T) {
+	withField := newTestSharing(t, 3, 5)
+	if err := withField.SetPrime(Prime61); err != nil {
+		t.Fatal(err)
+	}
+	withField.SetXOffset(100)
+	xor := newTestSharing(t, 4, 4)
+	if err := xor.SetScheme(SchemeXOR); err != nil {
+		t.Fatal(err)
+	}
+
+	secret := big.NewInt(31337)
+	for name, sss := range map[string]*ShamirSecretSharing{"default": newTestSharing(t, 2, 3), "prime61 offset": withField, "xor": xor} {
+		data, err := sss.SerializeConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		restored, err := DeserializeConfig(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if restored.threshold != sss.threshold || restored.numShares != sss.numShares ||
+			restored.prime.Cmp(sss.prime) != 0 || restored.xOffset != sss.xOffset || restored.Scheme() != sss.Scheme() {
+			t.Errorf("%s: restored %s from %s", name, mustSerialize(t, restored), data)
+		}
+		// Shares from the original reconstruct under the restored scheme
+		if got := restored.ReconstructSecret(sss.GenerateShares(secret)); got.Cmp(secret) != 0 {
+			t.Errorf("%s: restored scheme reconstructs %s, want %s", name, got, secret)
+		}
+	}
+}
+
+func TestDeserializeConfigInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"not json":         `{`,
+		"threshold":        `{"threshold": 4, "num_shares": 3, "prime": "2147483647"}`,
+		"composite prime":  `{"threshold": 2, "num_shares": 3, "prime": "2147483649"}`,
+		"bad prime":        `{"threshold": 2, "num_shares": 3, "prime": "x"}`,
+		"negative offset":  `{"threshold": 2, "num_shares": 3, "prime": "2147483647", "x_offset": -1}`,
+		"unknown scheme":   `{"threshold": 3, "num_shares": 3, "prime": "2147483647", "scheme": "rot13"}`,
+		"xor below quorum": `{"threshold": 2, "num_shares": 3, "prime": "2147483647", "scheme": "xor"}`,
+	} {
+		if _, err := DeserializeConfig([]byte(data)); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: got %v, want ErrInvalidConfig", name, err)
+		}
+	}
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	share := sss.GenerateShares(big.NewInt(7))[1]
+	env, err := NewEnvelope(sss, share, "holder-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, got, holder, err := OpenEnvelope(env)
+	if err != nil || holder != "holder-2" || got.X.Cmp(share.X) != 0 || restored.threshold != 2 {
+		t.Fatalf("OpenEnvelope = %v, %v, %q, %v", restored, got, holder, err)
+	}
+	if _, _, _, err := OpenEnvelope(ShareEnvelope{Config: env.Config}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("empty share: got %v, want ErrInvalidConfig", err)
+	}
+}
+
+// mustSerialize returns sss's configuration as a string for messages
+func mustSerialize(t *testing.T, sss *ShamirSecretSharing) string {
+	t.Helper()
+	data, err := sss.SerializeConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestNewShamirSecretSharingFromEnv(t *testing.T) {
+	t.Setenv("SSS_THRESHOLD", "3")
+	t.Setenv("SSS_NUM_SHARES", "5")
+	sss, err := NewShamirSecretSharingFromEnv()
+	if err != nil || sss.threshold != 3 || sss.numShares != 5 {
+		t.Fatalf("got %v, %v; want a 3-of-5 scheme", sss, err)
+	}
+
+	if sss.prime.Cmp(Prime31) != 0 {
+		t.Fatalf("default prime = %v, want Prime31", sss.prime)
+	}
+
+	for bits, want := range map[string]*big.Int{"31": Prime31, "61": Prime61, "127": Prime127, "521": Prime521} {
+		t.Setenv("SSS_PRIME_BITS", bits)
+		sss, err := NewShamirSecretSharingFromEnv()
+		if err != nil || sss.prime.Cmp(want) != 0 {
+			t.Fatalf("SSS_PRIME_BITS=%s: got %v, %v; want %v", bits, sss, err, want)
+		}
+	}
+	t.Setenv("SSS_PRIME_BITS", "64")
+	if _, err := NewShamirSecretSharingFromEnv(); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("SSS_PRIME_BITS=64: got %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestNewShamirSecretSharingFromEnvErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name                 string
+		threshold, numShares string
+		want                 error
+	}{
+		{"missing threshold", "", "5", ErrMissingEnvVar},
+		{"missing share count", "3", "", ErrMissingEnvVar},
+		{"non-integer", "three", "5", ErrInvalidConfig},
+		{"non-integer share count", "3", "5.0", ErrInvalidConfig},
+		{"threshold above shares", "6", "5", ErrInvalidThreshold},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("SSS_THRESHOLD", tc.threshold)
+			t.Setenv("SSS_NUM_SHARES", tc.numShares)
+			if _, err := NewShamirSecretSharingFromEnv(); !errors.Is(err, tc.want) {
+				t.Fatalf("got %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	t.Setenv("SSS_THRESHOLD", "2")
+	t.Setenv("SSS_NUM_SHARES", "3")
+	t.Setenv("SSS_PRIME_BITS", "")
+	if _, err := NewShamirSecretSharingFromEnv(); !errors.Is(err, ErrMissingEnvVar) || !strings.Contains(err.Error(), "SSS_PRIME_BITS") {
+		t.Fatalf("empty SSS_PRIME_BITS: got %v, want ErrMissingEnvVar naming it", err)
+	}
+}
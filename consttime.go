@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrDuplicateShare is returned when two shares have the same x-coordinate
+// mod the prime, or a share sits at x = 0, so no unique polynomial fits them
+var ErrDuplicateShare = errors.New("duplicate or zero share x-coordinate")
+
+// ConstantTimeReconstruct is ReconstructSecret using ConstantTimeLagrange for
+// shares from holders who may be timing the reconstruction
+func (sss *ShamirSecretSharing) ConstantTimeReconstruct(shares []Point) (*big.Int, error) {
+	needed := sss.sharesNeeded()
+	if len(shares) < needed {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrInsufficientShares, len(shares), needed)
+	}
+	if err := sss.checkSharePoints(shares[:needed]); err != nil {
+		return nil, err
+	}
+	if sss.usesXOR() {
+		return sss.xorCombine(shares), nil
+	}
+	return ConstantTimeLagrange(shares[:needed], sss.prime)
+}
+
+// ConstantTimeLagrange evaluates the polynomial through points at x = 0
+// like lagrangeAtZero, but performs the same sequence of operations whatever
+// the share values are: every basis term is reduced unconditionally and
+// denominators are inverted with a fixed-exponent Exp (Fermat's little
+// theorem) instead of the extended Euclidean algorithm, whose iteration count
+// depends on its input.
+//
+// This only removes branches from this code. math/big makes no constant-time
+// promises: operand lengths vary with the values, and Mul, Mod and Exp may
+// take different paths for different inputs. Treat it as reducing, not
+// eliminating, timing leakage. The prime must be prime. Points with a zero
+// or repeated x mod prime give ErrDuplicateShare; that check only looks at
+// the public x values, so it leaks nothing about the shares.
+func ConstantTimeLagrange(points []Point, prime *big.Int) (*big.Int, error) {
+	if err := checkDistinctX(points, prime); err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).Sub(prime, big.NewInt(2))
+	secret := new(big.Int)
+	numerator := new(big.Int)
+	denominator := new(big.Int)
+	term := new(big.Int)
+
+	for i := range points {
+		numerator.SetInt64(1)
+		denominator.SetInt64(1)
+
+		for j := range points {
+			if i == j {
+				// Depends only on the position, not on share data
+				continue
+			}
+			// numerator *= -xj, denominator *= xi - xj, both mod prime;
+			// Mod always yields a non-negative result, so no sign fix-up
+			term.Neg(points[j].X)
+			numerator.Mul(numerator, term).Mod(numerator, prime)
+			term.Sub(points[i].X, points[j].X)
+			denominator.Mul(denominator, term).Mod(denominator, prime)
+		}
+
+		denominator.Exp(denominator, exponent, prime)
+		term.Mul(numerator, denominator).Mod(term, prime)
+		term.Mul(term, points[i].Y)
+		secret.Add(secret, term).Mod(secret, prime)
+	}
+
+	return secret, nil
+}
+
+// checkDistinctX rejects points whose x values are zero or repeated mod
+// prime, either of which makes a Lagrange denominator zero
+func checkDistinctX(points []Point, prime *big.Int) error {
+	xs := make([]*big.Int, len(points))
+	for i, p := range points {
+		if p.X == nil || p.Y == nil {
+			return fmt.Errorf("%w: missing coordinate", ErrInvalidShare)
+		}
+		xs[i] = new(big.Int).Mod(p.X, prime)
+		if xs[i].Sign() == 0 {
+			return fmt.Errorf("%w: x = %s", ErrDuplicateShare, p.X)
+		}
+		for _, x := range xs[:i] {
+			if x.Cmp(xs[i]) == 0 {
+				return fmt.Errorf("%w: x = %s", ErrDuplicateShare, p.X)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestConstantTimeLagrangeMatchesLagrange(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	secret := big.NewInt(987654321)
+	shares := sss.GenerateShares(secret)
+	got, err := ConstantTimeLagrange(shares[1:4], sss.prime)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("ConstantTimeLagrange = %v, %v; want %s", got, err, secret)
+	}
+	if want := sss.lagrangeInterpolation(shares[1:4]); got.Cmp(want) != 0 {
+		t.Fatalf("ConstantTimeLagrange = %s, lagrangeInterpolation = %s", got, want)
+	}
+}
+
+func TestConstantTimeLagrangeRejectsDuplicateX(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	shares := sss.GenerateShares(big.NewInt(42))
+	// x = 1 + prime is x = 1 again in the field
+	wrapped := Point{X: new(big.Int).Add(shares[0].X, sss.prime), Y: shares[0].Y}
+	zero := Point{X: new(big.Int).Set(sss.prime), Y: big.NewInt(5)}
+
+	for name, points := range map[string][]Point{
+		"repeated x":        {shares[0], shares[1], shares[0]},
+		"x equal mod prime": {shares[0], shares[1], wrapped},
+		"zero x":            {zero, shares[1], shares[2]},
+	} {
+		if got, err := ConstantTimeLagrange(points, sss.prime); !errors.Is(err, ErrDuplicateShare) {
+			t.Errorf("%s: got %v, %v; want ErrDuplicateShare", name, got, err)
+		}
+	}
+
+	if _, err := sss.ConstantTimeReconstruct([]Point{shares[0], shares[0], shares[1]}); !errors.Is(err, ErrDuplicateShare) {
+		t.Errorf("ConstantTimeReconstruct with a repeated share: got %v, want ErrDuplicateShare", err)
+	}
+}
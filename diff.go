@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// FirstDifference returns the offset of the first byte at which a and b
+// differ, or -1 if they are identical. When one is a prefix of the other the
+// offset is the length of the shorter.
+func FirstDifference(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) != len(b) {
+		return n
+	}
+	return -1
+}
+
+// DiffTextSharesAgainst reconstructs the data in a text share file and
+// compares it with an original file. It returns the first differing offset,
+// or -1 when the shares still reproduce the original exactly.
+func (sss *ShamirSecretSharing) DiffTextSharesAgainst(sharesFile, originalPath string) (int, error) {
+	allShares, err := loadTextShares(sharesFile)
+	if err != nil {
+		return 0, err
+	}
+	reconstructed, err := sss.ReconstructArbitraryBytes(allShares)
+	if err != nil {
+		return 0, err
+	}
+
+	original, err := os.ReadFile(originalPath)
+	if err != nil {
+		return 0, err
+	}
+	return FirstDifference(reconstructed, original), nil
+}
+
+// printDiffResult reports the outcome of DiffTextSharesAgainst
+func printDiffResult(w io.Writer, offset int) {
+	if offset < 0 {
+		fmt.Fprintln(w, "Match: the shares reproduce the original exactly")
+		return
+	}
+	fmt.Fprintf(w, "Mismatch: first difference at byte offset %d\n", offset)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFirstDifference(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"same", "same", -1},
+		{"", "", -1},
+		{"abcd", "abXd", 2},
+		{"abc", "abcd", 3},
+		{"abcd", "ab", 2},
+	} {
+		if got := FirstDifference([]byte(tc.a), []byte(tc.b)); got != tc.want {
+			t.Errorf("FirstDifference(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestDiffTextSharesAgainst(t *testing.T) {
+	dir := t.TempDir()
+	original := []byte("backup \x00\xff contents")
+	sss := newTestSharing(t, 2, 3)
+	allShares, err := sss.ShareArbitraryBytes(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharesFile := filepath.Join(dir, "backup.shares")
+	if err := saveTextShares(allShares, sharesFile); err != nil {
+		t.Fatal(err)
+	}
+
+	originalPath := filepath.Join(dir, "original")
+	if err := os.WriteFile(originalPath, original, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if offset, err := sss.DiffTextSharesAgainst(sharesFile, originalPath); err != nil || offset != -1 {
+		t.Fatalf("matching original: got %d, %v; want -1", offset, err)
+	}
+
+	tampered := append([]byte(nil), original...)
+	tampered[8] ^= 1
+	if err := os.WriteFile(originalPath, tampered, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if offset, err := sss.DiffTextSharesAgainst(sharesFile, originalPath); err != nil || offset != 8 {
+		t.Fatalf("tampered original: got %d, %v; want 8", offset, err)
+	}
+
+	if _, err := sss.DiffTextSharesAgainst(sharesFile, filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Fatalf("missing original: got %v, want a not-exist error", err)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Hex digits of Y shown before a table entry is truncated
+const displayHexDigits = 16
+
+// shortHex formats a share value in hex, truncated for display
+func shortHex(p Point) string {
+	s := p.Y.Text(16)
+	if len(s) > displayHexDigits {
+		return s[:displayHexDigits] + "..."
+	}
+	return s
+}
+
+// PrintShares writes shares as a table of share number, x and hex y
+func PrintShares(shares []Point, w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	fmt.Fprintln(tw, "| Share #\t| X\t| Y (hex)\t|")
+	for i, p := range shares {
+		fmt.Fprintf(tw, "| %d\t| %s\t| %s\t|\n", i+1, p.X, shortHex(p))
+	}
+	return tw.Flush()
+}
+
+// PrintAllShares writes the shares of a text or image as one table grouped
+// by byte or pixel index; label names that index column, e.g. "Byte"
+func PrintAllShares(allShares [][]Point, w io.Writer, label string) error {
+	if label == "" {
+		label = "Secret"
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(tw, "| %s\t| Share #\t| X\t| Y (hex)\t|\n", label)
+	for i, shares := range allShares {
+		for j, p := range shares {
+			fmt.Fprintf(tw, "| %d\t| %d\t| %s\t| %s\t|\n", i, j+1, p.X, shortHex(p))
+		}
+	}
+	return tw.Flush()
+}
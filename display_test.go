@@ -0,0 +1,53 @@
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestPrintShares(t *testing.T) {
+	shares := []Point{
+		{X: big.NewInt(1), Y: big.NewInt(0xabc)},
+		{X: big.NewInt(12), Y: new(big.Int).Lsh(big.NewInt(1), 80)},
+	}
+	var out strings.Builder
+	if err := PrintShares(shares, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"| Share # | X  | Y (hex)             |\n" +
+		"| 1       | 1  | abc                 |\n" +
+		"| 2       | 12 | 1000000000000000... |\n"
+	if out.String() != want {
+		t.Fatalf("PrintShares wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestPrintAllShares(t *testing.T) {
+	allShares := [][]Point{
+		{{X: big.NewInt(1), Y: big.NewInt(10)}, {X: big.NewInt(2), Y: big.NewInt(255)}},
+		{{X: big.NewInt(1), Y: big.NewInt(0)}, {X: big.NewInt(2), Y: big.NewInt(4096)}},
+	}
+	var out strings.Builder
+	if err := PrintAllShares(allShares, &out, "Byte"); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"| Byte | Share # | X | Y (hex) |\n" +
+		"| 0    | 1       | 1 | a       |\n" +
+		"| 0    | 2       | 2 | ff      |\n" +
+		"| 1    | 1       | 1 | 0       |\n" +
+		"| 1    | 2       | 2 | 1000    |\n"
+	if out.String() != want {
+		t.Fatalf("PrintAllShares wrote\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := PrintAllShares(allShares[:1], &out, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "| Secret | Share #") {
+		t.Fatalf("default label: got %q", out.String())
+	}
+}
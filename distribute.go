@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CollectTimeout bounds how long CollectAndReconstruct waits for shares
+var CollectTimeout = 30 * time.Second
+
+// MultiWriteError aggregates the failures of a concurrent distribution
+type MultiWriteError struct {
+	Errors map[int]error // keyed by destination index
+}
+
+func (e *MultiWriteError) Error() string {
+	indices := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	parts := make([]string, len(indices))
+	for n, i := range indices {
+		parts[n] = fmt.Sprintf("destination %d: %v", i, e.Errors[i])
+	}
+	return fmt.Sprintf("failed to write %d share(s): %s", len(indices), strings.Join(parts, "; "))
+}
+
+func (e *MultiWriteError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// ShareAndDistribute shares a secret and writes share i to destinations[i],
+// each write running in its own goroutine. Every share is written as a single
+// "x y" line.
+func (sss *ShamirSecretSharing) ShareAndDistribute(secret *big.Int, destinations []io.Writer) error {
+	if len(destinations) != sss.numShares {
+		return fmt.Errorf("have %d destinations for %d shares", len(destinations), sss.numShares)
+	}
+
+	shares := sss.GenerateShares(secret)
+	errs := make([]error, len(destinations))
+
+	var wg sync.WaitGroup
+	for i, dest := range destinations {
+		wg.Add(1)
+		go func(i int, dest io.Writer) {
+			defer wg.Done()
+			_, errs[i] = fmt.Fprintf(dest, "%s %s\n", shares[i].X.String(), shares[i].Y.String())
+		}(i, dest)
+	}
+	wg.Wait()
+
+	failed := make(map[int]error)
+	for i, err := range errs {
+		if err != nil {
+			failed[i] = err
+		}
+	}
+	if len(failed) > 0 {
+		return &MultiWriteError{Errors: failed}
+	}
+	return nil
+}
+
+// CollectAndReconstruct reads one share line from each source concurrently
+// and reconstructs the secret from the first shares to arrive, as many as
+// reconstruction needs. A share repeating an x-coordinate already received
+// is ignored; a source that fails or sends an invalid share counts as
+// failed, and once too few sources are left to finish it gives up without
+// waiting for CollectTimeout. Sources that are still blocked when it returns
+// are abandoned, so callers should close them to release the reading
+// goroutines.
+func (sss *ShamirSecretSharing) CollectAndReconstruct(sources []io.Reader) (*big.Int, error) {
+	needed := sss.sharesNeeded()
+	if len(sources) < needed {
+		return nil, fmt.Errorf("%w: have %d sources, need %d", ErrInsufficientShares, len(sources), needed)
+	}
+
+	type result struct {
+		share Point
+		err   error
+	}
+
+	// Buffered so late readers never block after we stop listening
+	results := make(chan result, len(sources))
+	for _, src := range sources {
+		go func(src io.Reader) {
+			line, err := bufio.NewReader(src).ReadString('\n')
+			if err != nil && (err != io.EOF || line == "") {
+				results <- result{err: err}
+				return
+			}
+			share, err := parsePointLine(strings.TrimSpace(line))
+			if err == nil {
+				err = sss.checkSharePoints([]Point{share})
+			}
+			results <- result{share: share, err: err}
+		}(src)
+	}
+
+	timeout := time.After(CollectTimeout)
+	points := make([]Point, 0, needed)
+	seen := make(map[string]bool)
+	received := 0
+
+	for len(points) < needed {
+		select {
+		case r := <-results:
+			received++
+			// Duplicate x-coordinates add nothing to the interpolation
+			if r.err == nil && !seen[r.share.X.String()] {
+				seen[r.share.X.String()] = true
+				points = append(points, r.share)
+				continue
+			}
+			if pending := len(sources) - received; len(points)+pending < needed {
+				cause := r.err
+				if cause == nil {
+					cause = fmt.Errorf("duplicate share for x = %s", r.share.X)
+				}
+				return nil, fmt.Errorf("%w: only %d of %d sources can still deliver a share: %w",
+					ErrInsufficientShares, len(points)+pending, needed, cause)
+			}
+		case <-timeout:
+			return nil, fmt.Errorf("timed out after receiving %d of %d shares", len(points), needed)
+		}
+	}
+
+	prepared, err := sss.prepareShares([][]Point{points})
+	if err != nil {
+		return nil, err
+	}
+	return sss.ReconstructSecret(prepared[0]), nil
+}
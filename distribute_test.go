@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowWriter delays every write
+type slowWriter struct {
+	w     io.Writer
+	delay time.Duration
+}
+
+func (s slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	return s.w.Write(p)
+}
+
+// failingWriter rejects every write
+type failingWriter struct{}
+
+var errWriteFailed = errors.New("write failed")
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errWriteFailed }
+
+// distributeOverPipes shares secret over one io.Pipe per share and returns
+// the read ends, closing each write end once its share is written
+func distributeOverPipes(t *testing.T, sss *ShamirSecretSharing, secret *big.Int) []io.Reader {
+	t.Helper()
+	readers := make([]io.Reader, sss.numShares)
+	writers := make([]io.Writer, sss.numShares)
+	pipes := make([]*io.PipeWriter, sss.numShares)
+	for i := range readers {
+		r, w := io.Pipe()
+		readers[i], writers[i], pipes[i] = r, w, w
+	}
+	go func() {
+		err := sss.ShareAndDistribute(secret, writers)
+		for _, w := range pipes {
+			w.CloseWithError(err)
+		}
+	}()
+	return readers
+}
+
+func TestShareAndCollectOverPipes(t *testing.T) {
+	secret := big.NewInt(42)
+	for _, tc := range []struct {
+		name                 string
+		threshold, numShares int
+		prime                *big.Int
+		scheme               Scheme
+	}{
+		{"2-of-3", 2, 3, Prime31, SchemePolynomial},
+		{"3-of-3", 3, 3, Prime31, SchemePolynomial},
+		{"3-of-3 xor", 3, 3, Prime31, SchemeXOR},
+		{"2-of-3 prime61", 2, 3, Prime61, SchemePolynomial},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sss := newTestSharing(t, tc.threshold, tc.numShares)
+			if err := sss.SetPrime(tc.prime); err != nil {
+				t.Fatal(err)
+			}
+			if err := sss.SetScheme(tc.scheme); err != nil {
+				t.Fatal(err)
+			}
+			got, err := sss.CollectAndReconstruct(distributeOverPipes(t, sss, secret))
+			if err != nil || got.Cmp(secret) != 0 {
+				t.Fatalf("CollectAndReconstruct = %v, %v; want %s", got, err, secret)
+			}
+		})
+	}
+}
+
+func TestShareAndDistributeSlowAndFailingWriters(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	var first, second strings.Builder
+	destinations := []io.Writer{
+		slowWriter{w: &first, delay: 20 * time.Millisecond},
+		failingWriter{},
+		&second,
+	}
+
+	err := sss.ShareAndDistribute(big.NewInt(42), destinations)
+	var multi *MultiWriteError
+	if !errors.As(err, &multi) {
+		t.Fatalf("got %v, want a *MultiWriteError", err)
+	}
+	if len(multi.Errors) != 1 || !errors.Is(multi.Errors[1], errWriteFailed) {
+		t.Fatalf("failures = %v, want only destination 1", multi.Errors)
+	}
+
+	// The slow and healthy destinations still got their shares
+	sources := []io.Reader{strings.NewReader(first.String()), strings.NewReader(second.String())}
+	got, err := sss.CollectAndReconstruct(sources)
+	if err != nil || got.Int64() != 42 {
+		t.Fatalf("CollectAndReconstruct = %v, %v; want 42", got, err)
+	}
+}
+
+func TestCollectAndReconstructGivesUpEarly(t *testing.T) {
+	defer func(old time.Duration) { CollectTimeout = old }(CollectTimeout)
+	CollectTimeout = time.Minute
+
+	sss := newTestSharing(t, 2, 3)
+	shares := sss.GenerateShares(big.NewInt(42))
+	line := shares[0].X.String() + " " + shares[0].Y.String() + "\n"
+
+	for name, sources := range map[string][]io.Reader{
+		"duplicate x":   {strings.NewReader(line), strings.NewReader(line)},
+		"failed source": {strings.NewReader(line), strings.NewReader("not a share\n")},
+		"invalid share": {strings.NewReader(line), strings.NewReader("2 -5\n")},
+	} {
+		start := time.Now()
+		if _, err := sss.CollectAndReconstruct(sources); !errors.Is(err, ErrInsufficientShares) {
+			t.Errorf("%s: got %v, want ErrInsufficientShares", name, err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("%s: took %v, should not wait for the timeout", name, elapsed)
+		}
+	}
+}
+
+func TestCollectAndReconstructIgnoresDuplicates(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	shares := sss.GenerateShares(big.NewInt(42))
+	lines := make([]string, len(shares))
+	for i, s := range shares {
+		lines[i] = s.X.String() + " " + s.Y.String() + "\n"
+	}
+	sources := []io.Reader{strings.NewReader(lines[0]), strings.NewReader(lines[0]), strings.NewReader(lines[2])}
+	got, err := sss.CollectAndReconstruct(sources)
+	if err != nil || got.Int64() != 42 {
+		t.Fatalf("CollectAndReconstruct = %v, %v; want 42", got, err)
+	}
+}
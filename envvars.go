@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+)
+
+// envShareKey names the variable holding one coordinate of a share
+func envShareKey(prefix string, secret int, coord string, share int) string {
+	return fmt.Sprintf("%s_SHARE_%d_%s_%d", prefix, secret, coord, share)
+}
+
+// FormatSharesAsEnvVars lays shares out as environment variables named
+// PREFIX_SHARE_<secret>_X_<share> and PREFIX_SHARE_<secret>_Y_<share>
+func FormatSharesAsEnvVars(allShares [][]Point, prefix string) map[string]string {
+	env := make(map[string]string)
+	for i, shares := range allShares {
+		for j, p := range shares {
+			env[envShareKey(prefix, i, "X", j)] = p.X.String()
+			env[envShareKey(prefix, i, "Y", j)] = p.Y.String()
+		}
+	}
+	return env
+}
+
+// ParseSharesFromEnvVars reverses FormatSharesAsEnvVars. Secrets and shares
+// are read in order until the first index with no X variable.
+func ParseSharesFromEnvVars(env map[string]string, prefix string) ([][]Point, error) {
+	var allShares [][]Point
+
+	for i := 0; ; i++ {
+		var shares []Point
+		for j := 0; ; j++ {
+			xKey, yKey := envShareKey(prefix, i, "X", j), envShareKey(prefix, i, "Y", j)
+			xStr, ok := env[xKey]
+			if !ok {
+				break
+			}
+			yStr, ok := env[yKey]
+			if !ok {
+				return nil, fmt.Errorf("%s is set but %s is missing", xKey, yKey)
+			}
+
+			x, ok := new(big.Int).SetString(xStr, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid value %q for %s", xStr, xKey)
+			}
+			y, ok := new(big.Int).SetString(yStr, 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid value %q for %s", yStr, yKey)
+			}
+			shares = append(shares, Point{X: x, Y: y})
+		}
+		if len(shares) == 0 {
+			break
+		}
+		allShares = append(allShares, shares)
+	}
+
+	return allShares, nil
+}
+
+// saveEnvFile writes variables as a shell-sourceable .env file
+func saveEnvFile(env map[string]string, filename string) error {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, key := range keys {
+		// Values are decimal integers, so no quoting is needed
+		fmt.Fprintf(writer, "%s=%s\n", key, env[key])
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
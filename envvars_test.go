@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnvVarsRoundTrip(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	allShares := mustShareText(t, sss, "env")
+	env := FormatSharesAsEnvVars(allShares, "APP")
+
+	if len(env) != 3*3*2 {
+		t.Fatalf("got %d variables, want 18", len(env))
+	}
+	if got, want := env["APP_SHARE_1_Y_2"], allShares[1][2].Y.String(); got != want {
+		t.Fatalf("APP_SHARE_1_Y_2 = %q, want %q", got, want)
+	}
+
+	parsed, err := ParseSharesFromEnvVars(env, "APP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != len(allShares) {
+		t.Fatalf("parsed %d secrets, want %d", len(parsed), len(allShares))
+	}
+	for i := range allShares {
+		for j, p := range allShares[i] {
+			if parsed[i][j].X.Cmp(p.X) != 0 || parsed[i][j].Y.Cmp(p.Y) != 0 {
+				t.Fatalf("share %d of secret %d = %v, want %v", j, i, parsed[i][j], p)
+			}
+		}
+	}
+	if other, err := ParseSharesFromEnvVars(env, "OTHER"); err != nil || len(other) != 0 {
+		t.Fatalf("other prefix: got %v, %v", other, err)
+	}
+}
+
+func TestParseSharesFromEnvVarsErrors(t *testing.T) {
+	for name, env := range map[string]map[string]string{
+		"missing y": {"P_SHARE_0_X_0": "1"},
+		"bad x":     {"P_SHARE_0_X_0": "one", "P_SHARE_0_Y_0": "5"},
+		"bad y":     {"P_SHARE_0_X_0": "1", "P_SHARE_0_Y_0": "0x5"},
+	} {
+		if _, err := ParseSharesFromEnvVars(env, "P"); err == nil {
+			t.Errorf("%s: parsed without error", name)
+		}
+	}
+}
+
+func TestSaveEnvFile(t *testing.T) {
+	env := FormatSharesAsEnvVars([][]Point{{{X: big.NewInt(1), Y: big.NewInt(42)}}}, "K")
+	path := filepath.Join(t.TempDir(), "shares.env")
+	if err := saveEnvFile(env, path); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	read := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			t.Fatalf("line %q is not KEY=value", scanner.Text())
+		}
+		read[key] = value
+	}
+	if read["K_SHARE_0_X_0"] != "1" || read["K_SHARE_0_Y_0"] != "42" || len(read) != 2 {
+		t.Fatalf("env file holds %v", read)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("env file mode %v, want 0600", info.Mode())
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Preset field primes, all Mersenne primes. Larger fields hold larger
+// secrets and let packed sharing fit more bytes per secret. Prime521 is the
+// only one large enough for commitments (see NewFeldmanVSS).
+var (
+	Prime31  = mustPrime(31)  // 2^31 - 1, the default
+	Prime61  = mustPrime(61)  // 2^61 - 1
+	Prime127 = mustPrime(127) // 2^127 - 1
+	Prime521 = mustPrime(521) // 2^521 - 1
+)
+
+// fieldPresets maps the names accepted by PrimeByName to their primes
+var fieldPresets = map[string]*big.Int{
+	"prime31":  Prime31,
+	"prime61":  Prime61,
+	"prime127": Prime127,
+	"prime521": Prime521,
+}
+
+// mustPrime returns 2^bits - 1, panicking if it is not prime
+func mustPrime(bits uint) *big.Int {
+	p := new(big.Int).Lsh(big.NewInt(1), bits)
+	p.Sub(p, big.NewInt(1))
+	if !p.ProbablyPrime(32) {
+		panic(fmt.Sprintf("2^%d-1 is not prime", bits))
+	}
+	return p
+}
+
+// FieldNames lists the preset names accepted by PrimeByName
+func FieldNames() []string {
+	names := make([]string, 0, len(fieldPresets))
+	for name := range fieldPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PrimeByName returns a copy of the named preset prime
+func PrimeByName(name string) (*big.Int, error) {
+	prime, ok := fieldPresets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %q (choose from %s)",
+			ErrInvalidPrime, name, strings.Join(FieldNames(), ", "))
+	}
+	return new(big.Int).Set(prime), nil
+}
+
+// NewShamirSecretSharingWithField creates an instance over a preset field
+// chosen by name, such as "prime61"
+func NewShamirSecretSharingWithField(threshold, numShares int, field string) (*ShamirSecretSharing, error) {
+	prime, err := PrimeByName(field)
+	if err != nil {
+		return nil, err
+	}
+	sss, err := NewShamirSecretSharing(threshold, numShares)
+	if err != nil {
+		return nil, err
+	}
+	if err := sss.SetPrime(prime); err != nil {
+		return nil, err
+	}
+	return sss, nil
+}
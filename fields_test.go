@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestFieldPresets(t *testing.T) {
+	secret := big.NewInt(1_000_000_007)
+	for _, name := range FieldNames() {
+		prime, err := PrimeByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !prime.ProbablyPrime(32) {
+			t.Errorf("%s is not prime", name)
+		}
+		sss, err := NewShamirSecretSharingWithField(3, 5, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares := sss.GenerateShares(secret)
+		if got := sss.ReconstructSecret(shares[2:]); got.Cmp(secret) != 0 {
+			t.Errorf("%s: reconstructed %s, want %s", name, got, secret)
+		}
+		if text, err := sss.ReconstructText(mustShareText(t, sss, "field")); err != nil || text != "field" {
+			t.Errorf("%s: text round trip got %q, %v", name, text, err)
+		}
+	}
+}
+
+// mustShareText shares text or fails the test
+func mustShareText(t *testing.T, sss *ShamirSecretSharing, text string) [][]Point {
+	t.Helper()
+	allShares, err := sss.ShareText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return allShares
+}
+
+func TestInstancesWithDifferentPrimes(t *testing.T) {
+	small := newTestSharing(t, 3, 5)
+	large := newTestSharing(t, 3, 5)
+	if err := large.SetPrime(Prime61); err != nil {
+		t.Fatal(err)
+	}
+	if PRIME.Cmp(Prime31) != 0 || small.Prime().Cmp(Prime31) != 0 {
+		t.Fatalf("SetPrime on one instance changed PRIME to %s or the other instance to %s", PRIME, small.Prime())
+	}
+
+	secret := big.NewInt(1234567)
+	smallShares, largeShares := small.GenerateShares(secret), large.GenerateShares(secret)
+	if got := small.ReconstructSecret(smallShares); got.Cmp(secret) != 0 {
+		t.Fatalf("own field: got %s", got)
+	}
+	if got := large.ReconstructSecret(largeShares); got.Cmp(secret) != 0 {
+		t.Fatalf("own field: got %s", got)
+	}
+
+	// Small-field shares are valid points in the large field. Lagrange
+	// weights are small rationals, so a single secret still comes out right
+	// whenever the weighted sum of the shares doesn't wrap the small prime,
+	// which happens often; every byte of a text doing so does not
+	text := strings.Repeat("field", 13)
+	if got, err := large.ReconstructText(mustShareText(t, small, text)); err == nil && got == text {
+		t.Fatal("Prime31 text shares reconstructed under Prime61")
+	}
+	// Large-field shares almost surely have a y the small field rejects; if
+	// not, they still must not recover the secret
+	if got, _, err := small.ReconstructSecretVerbose(largeShares); err == nil && got.Cmp(secret) == 0 {
+		t.Fatal("Prime61 shares reconstructed under Prime31")
+	}
+}
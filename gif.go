@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+)
+
+// FrameShares holds the shares of one GIF frame's palette indices
+type FrameShares struct {
+	Shares   [][]Point
+	Bounds   image.Rectangle
+	Delay    int  // in 100ths of a second
+	Disposal byte // one of the gif.Disposal* values, or 0
+}
+
+// GIFMetadata holds the parts of an animated GIF that are not secret-shared
+type GIFMetadata struct {
+	Width, Height   int
+	LoopCount       int
+	BackgroundIndex byte
+	Palettes        []color.Palette // one per frame
+}
+
+// ShareGIF shares every frame of an animated GIF. Pixels are shared as
+// palette indices, so frames reconstruct exactly with their own palettes.
+func (sss *ShamirSecretSharing) ShareGIF(gifPath string) ([]FrameShares, GIFMetadata, error) {
+	file, err := os.Open(gifPath)
+	if err != nil {
+		return nil, GIFMetadata{}, err
+	}
+	defer file.Close()
+
+	config, err := gif.DecodeConfig(file)
+	if err != nil {
+		return nil, GIFMetadata{}, err
+	}
+	if err := checkImageSize(config.Width, config.Height); err != nil {
+		return nil, GIFMetadata{}, err
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, GIFMetadata{}, err
+	}
+
+	g, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, GIFMetadata{}, err
+	}
+
+	meta := GIFMetadata{
+		Width:           g.Config.Width,
+		Height:          g.Config.Height,
+		LoopCount:       g.LoopCount,
+		BackgroundIndex: g.BackgroundIndex,
+	}
+	frames := make([]FrameShares, len(g.Image))
+
+	for i, frame := range g.Image {
+		frames[i] = FrameShares{
+			Shares: sss.sharePixels(palettedIndices(frame)),
+			Bounds: frame.Bounds(),
+			Delay:  g.Delay[i],
+		}
+		if g.Disposal != nil {
+			frames[i].Disposal = g.Disposal[i]
+		}
+		meta.Palettes = append(meta.Palettes, frame.Palette)
+	}
+
+	return frames, meta, nil
+}
+
+// ReconstructGIF rebuilds an animated GIF from ShareGIF output
+func (sss *ShamirSecretSharing) ReconstructGIF(frames []FrameShares, meta GIFMetadata, outputPath string) error {
+	if len(frames) == 0 {
+		return errors.New("no frames to reconstruct")
+	}
+	if len(meta.Palettes) != len(frames) {
+		return fmt.Errorf("have %d palettes for %d frames", len(meta.Palettes), len(frames))
+	}
+
+	g := &gif.GIF{
+		LoopCount:       meta.LoopCount,
+		BackgroundIndex: meta.BackgroundIndex,
+		Config: image.Config{
+			Width:      meta.Width,
+			Height:     meta.Height,
+			ColorModel: meta.Palettes[0],
+		},
+	}
+	for i, frame := range frames {
+		indices, err := sss.ReconstructImageBytes(frame.Shares, frame.Bounds.Dx(), frame.Bounds.Dy())
+		if err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+
+		img := image.NewPaletted(frame.Bounds, meta.Palettes[i])
+		copy(img.Pix, indices)
+
+		g.Image = append(g.Image, img)
+		g.Delay = append(g.Delay, frame.Delay)
+		g.Disposal = append(g.Disposal, frame.Disposal)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(file, g); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGIFRoundTrip(t *testing.T) {
+	palettes := []color.Palette{
+		{color.Black, color.White, color.RGBA{R: 255, A: 255}},
+		{color.RGBA{B: 255, A: 255}, color.RGBA{G: 255, A: 255}},
+	}
+	src := &gif.GIF{LoopCount: 3}
+	for i, palette := range palettes {
+		frame := image.NewPaletted(image.Rect(0, 0, 8, 8), palette)
+		for p := range frame.Pix {
+			frame.Pix[p] = uint8((p + i) % len(palette))
+		}
+		src.Image = append(src.Image, frame)
+		src.Delay = append(src.Delay, 10*(i+1))
+		src.Disposal = append(src.Disposal, gif.DisposalBackground)
+	}
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "in.gif")
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(srcPath, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sss := newTestSharing(t, 2, 3)
+	frames, meta, err := sss.ShareGIF(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 || meta.Width != 8 || meta.Height != 8 || meta.LoopCount != 3 {
+		t.Fatalf("ShareGIF = %d frames, %+v", len(frames), meta)
+	}
+
+	// Reconstruct from only the last two shares of every pixel
+	for _, frame := range frames {
+		for i, shares := range frame.Shares {
+			frame.Shares[i] = shares[1:]
+		}
+	}
+	outPath := filepath.Join(dir, "out.gif")
+	if err := sss.ReconstructGIF(frames, meta, outPath); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	got, err := gif.DecodeAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Image) != 2 || got.LoopCount != 3 {
+		t.Fatalf("got %d frames, loop count %d; want 2 and 3", len(got.Image), got.LoopCount)
+	}
+	for i, frame := range got.Image {
+		if !bytes.Equal(frame.Pix, src.Image[i].Pix) {
+			t.Errorf("frame %d pixels differ", i)
+		}
+		if got.Delay[i] != src.Delay[i] || got.Disposal[i] != gif.DisposalBackground {
+			t.Errorf("frame %d: delay %d, disposal %d", i, got.Delay[i], got.Disposal[i])
+		}
+		want := palettes[i][1]
+		if frame.Palette[1] != color.RGBAModel.Convert(want) {
+			t.Errorf("frame %d: palette entry %v, want %v", i, frame.Palette[1], want)
+		}
+	}
+
+	if err := sss.ReconstructGIF(frames, GIFMetadata{Width: 8, Height: 8}, outPath); err == nil {
+		t.Fatal("ReconstructGIF without palettes succeeded")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math/big"
+)
+
+// ShareImageWithDepth shares an image like ShareImage but keeps 16-bit
+// grayscale images at full precision. It returns the bit depth (8 or 16)
+// that must be recorded alongside the shares.
+func (sss *ShamirSecretSharing) ShareImageWithDepth(imagePath string) ([][]Point, int, int, int, error) {
+	img, err := decodeImageFile(imagePath)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	allShares, width, height, depth := sss.shareImageWithDepth(img)
+	return allShares, width, height, depth, nil
+}
+
+// shareImageWithDepth is ShareImageWithDepth for an already decoded image
+func (sss *ShamirSecretSharing) shareImageWithDepth(img image.Image) ([][]Point, int, int, int) {
+	gray16, ok := img.(*image.Gray16)
+	if !ok {
+		pixels, width, height := grayPixels(img, sss.conversion)
+		return sss.sharePixels(pixels), width, height, 8
+	}
+
+	bounds := gray16.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	total := width * height
+
+	// Each 16-bit value fits comfortably inside the prime field
+	allShares := make([][]Point, 0, total)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			secret := big.NewInt(int64(gray16.Gray16At(x, y).Y))
+			allShares = append(allShares, sss.GenerateShares(secret))
+			sss.reportProgress(len(allShares), total)
+		}
+	}
+
+	return allShares, width, height, 16
+}
+
+// ReconstructImageWithDepth reconstructs an image, writing a 16-bit
+// grayscale PNG when depth is 16 and an 8-bit one otherwise
+func (sss *ShamirSecretSharing) ReconstructImageWithDepth(allShares [][]Point, width, height, depth int, outputPath string) error {
+	if err := checkWritable(outputPath); err != nil {
+		return err
+	}
+	img, err := sss.reconstructImageWithDepth(allShares, width, height, depth)
+	if err != nil {
+		return err
+	}
+	return writePNGFile(img, outputPath)
+}
+
+// reconstructImageWithDepth builds a 16-bit grayscale image when depth is 16
+// and an 8-bit one otherwise
+func (sss *ShamirSecretSharing) reconstructImageWithDepth(allShares [][]Point, width, height, depth int) (image.Image, error) {
+	if depth != 16 {
+		return sss.ReconstructImage(allShares, width, height)
+	}
+
+	total := width * height
+	if len(allShares) != total {
+		return nil, fmt.Errorf("%w: have shares for %d pixels, a %dx%d image has %d",
+			ErrDimensionMismatch, len(allShares), width, height, total)
+	}
+	allShares, err := sss.prepareShares(allShares)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewGray16(image.Rect(0, 0, width, height))
+	for i, shares := range allShares {
+		secret := sss.ReconstructSecret(shares)
+		img.SetGray16(i%width, i/width, color.Gray16{Y: uint16(secret.Int64())})
+		sss.reportProgress(i+1, total)
+	}
+	return img, nil
+}
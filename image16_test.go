@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradient16 builds a 16-bit grayscale image whose values span the full range
+func gradient16(width, height int) *image.Gray16 {
+	img := image.NewGray16(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray16(x, y, color.Gray16{Y: uint16((y*width + x) * 65535 / (width*height - 1))})
+		}
+	}
+	return img
+}
+
+func TestShareImage16BitRoundTrip(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	src := gradient16(16, 8)
+	allShares, width, height, depth := sss.shareImageWithDepth(src)
+	if depth != 16 {
+		t.Fatalf("depth = %d, want 16", depth)
+	}
+
+	img, err := sss.reconstructImageWithDepth(allShares, width, height, depth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			want := color.Gray16Model.Convert(src.At(x, y)).(color.Gray16)
+			got := color.Gray16Model.Convert(img.At(x, y)).(color.Gray16)
+			if got != want {
+				t.Fatalf("pixel (%d, %d) = %d, want %d", x, y, got.Y, want.Y)
+			}
+		}
+	}
+}
+
+func TestReconstructImage16BitRejectsBadShares(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	allShares, width, height, depth := sss.shareImageWithDepth(gradient16(4, 4))
+
+	tooFew := append([][]Point(nil), allShares...)
+	tooFew[5] = tooFew[5][:1]
+	if _, err := sss.reconstructImageWithDepth(tooFew, width, height, depth); !errors.Is(err, ErrInsufficientShares) {
+		t.Errorf("too few shares: got %v, want ErrInsufficientShares", err)
+	}
+
+	duplicated := append([][]Point(nil), allShares...)
+	duplicated[5] = []Point{allShares[5][0], allShares[5][0]}
+	if _, err := sss.reconstructImageWithDepth(duplicated, width, height, depth); !errors.Is(err, ErrInsufficientShares) {
+		t.Errorf("duplicated share: got %v, want ErrInsufficientShares", err)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Quality used when a reconstruction is written as JPEG
+const jpegQuality = 95
+
+// imageExtensions maps output file extensions to the format they select
+var imageExtensions = map[string]string{
+	".png":  "png",
+	".jpg":  "jpeg",
+	".jpeg": "jpeg",
+	".gif":  "gif",
+}
+
+// formatExtensions gives the extension appended for each writable format
+var formatExtensions = map[string]string{
+	"png":  ".png",
+	"jpeg": ".jpg",
+	"gif":  ".gif",
+}
+
+// imageOutputPath resolves where and in which format a reconstruction is
+// written. An image extension on outputPath overrides the recorded source
+// format; otherwise the extension of the source format, or PNG when it is
+// unknown or cannot be written, is appended.
+func imageOutputPath(outputPath, sourceFormat string) (string, string) {
+	if format, ok := imageExtensions[strings.ToLower(filepath.Ext(outputPath))]; ok {
+		return outputPath, format
+	}
+	if ext, ok := formatExtensions[sourceFormat]; ok {
+		return outputPath + ext, sourceFormat
+	}
+	return outputPath + ".png", "png"
+}
+
+// writeImageFile saves an image in the named format
+func writeImageFile(img image.Image, outputPath, format string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch format {
+	case "png":
+		err = png.Encode(file, img)
+	case "jpeg":
+		err = jpeg.Encode(file, img, &jpeg.Options{Quality: jpegQuality})
+	case "gif":
+		err = gif.Encode(file, grayPaletted(img), nil)
+	default:
+		err = fmt.Errorf("cannot write images as %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// grayPaletted converts a grayscale image to a 256-gray paletted image, so
+// GIF output keeps every gray level instead of quantizing to the web palette.
+// Other images are returned unchanged.
+func grayPaletted(img image.Image) image.Image {
+	switch img.(type) {
+	case *image.Gray, *image.Gray16:
+	default:
+		return img
+	}
+	palette := make(color.Palette, 256)
+	for i := range palette {
+		palette[i] = color.Gray{Y: uint8(i)}
+	}
+	paletted := image.NewPaletted(img.Bounds(), palette)
+	draw.Draw(paletted, paletted.Rect, img, img.Bounds().Min, draw.Src)
+	return paletted
+}
+
+// ReconstructImageToFile reconstructs an image described by meta and writes
+// it to outputPath. Without an image extension on outputPath the image is
+// written in the source format recorded at sharing time, so sharing a JPEG
+// gives a JPEG back. It returns the path actually written.
+func (sss *ShamirSecretSharing) ReconstructImageToFile(allShares [][]Point, width, height int, meta ShareMetadata, outputPath string) (string, error) {
+	outputPath, format := imageOutputPath(outputPath, meta.Format)
+	if err := checkWritable(outputPath); err != nil {
+		return "", err
+	}
+
+	img, err := sss.reconstructImageMeta(allShares, width, height, meta)
+	if err != nil {
+		return "", err
+	}
+	return outputPath, writeImageFile(img, outputPath, format)
+}
+
+// checkWritable fails early if outputPath cannot be opened for writing, so
+// an unwritable destination is reported before any expensive work rather
+// than after it. An existing file is left untouched, and one created by the
+// check is removed again.
+func checkWritable(outputPath string) error {
+	_, statErr := os.Stat(outputPath)
+	file, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	file.Close()
+	if errors.Is(statErr, fs.ErrNotExist) {
+		return os.Remove(outputPath)
+	}
+	return nil
+}
+
+// reconstructImageMeta reconstructs a palette or grayscale image as the
+// share file's header describes
+func (sss *ShamirSecretSharing) reconstructImageMeta(allShares [][]Point, width, height int, meta ShareMetadata) (image.Image, error) {
+	if meta.Palette != nil {
+		return sss.ReconstructImagePalette(allShares, width, height, meta.Palette)
+	}
+	return sss.reconstructImageWithDepth(allShares, width, height, meta.Depth)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"image/jpeg"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReconstructImageKeepsSourceFormat(t *testing.T) {
+	dir := t.TempDir()
+	src := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 4)
+	}
+	jpegPath := filepath.Join(dir, "photo.jpg")
+	file, err := os.Create(jpegPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := jpeg.Encode(file, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	img, format, err := decodeImageFileFormat(jpegPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "jpeg" {
+		t.Fatalf("detected format %q, want jpeg", format)
+	}
+	sss := newTestSharing(t, 2, 3)
+	allShares, width, height, _ := sss.shareImageWithDepth(img)
+	sharePath := filepath.Join(dir, "photo_shares.txt")
+	if err := saveImageSharesMeta(allShares, width, height, ShareMetadata{Threshold: 2, Format: format}, sharePath); err != nil {
+		t.Fatal(err)
+	}
+	loaded, width, height, meta, err := loadImageSharesMeta(sharePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Format != "jpeg" {
+		t.Fatalf("header records format %q, want jpeg", meta.Format)
+	}
+
+	tests := []struct {
+		output, wantPath, wantFormat string
+	}{
+		{"restored", "restored.jpg", "jpeg"},
+		{"restored.png", "restored.png", "png"},
+		{"restored.GIF", "restored.GIF", "gif"},
+	}
+	for _, tt := range tests {
+		written, err := sss.ReconstructImageToFile(loaded, width, height, meta, filepath.Join(dir, tt.output))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if written != filepath.Join(dir, tt.wantPath) {
+			t.Errorf("output %q: wrote %q, want %q", tt.output, written, tt.wantPath)
+		}
+		_, gotFormat, err := decodeImageFileFormat(written)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotFormat != tt.wantFormat {
+			t.Errorf("output %q: wrote %s, want %s", tt.output, gotFormat, tt.wantFormat)
+		}
+	}
+}
+
+func TestImageOutputPath(t *testing.T) {
+	tests := []struct {
+		output, source, wantPath, wantFormat string
+	}{
+		{"out", "jpeg", "out.jpg", "jpeg"},
+		{"out", "gif", "out.gif", "gif"},
+		{"out", "", "out.png", "png"},
+		{"out", "webp", "out.png", "png"},
+		{"out.jpeg", "png", "out.jpeg", "jpeg"},
+		{"out.tar", "jpeg", "out.tar.jpg", "jpeg"},
+	}
+	for _, tt := range tests {
+		path, format := imageOutputPath(tt.output, tt.source)
+		if path != tt.wantPath || format != tt.wantFormat {
+			t.Errorf("imageOutputPath(%q, %q) = %q, %q; want %q, %q", tt.output, tt.source, path, format, tt.wantPath, tt.wantFormat)
+		}
+	}
+}
+
+func TestReconstructImageUnwritablePath(t *testing.T) {
+	dir := t.TempDir()
+	sss := newTestSharing(t, 2, 3)
+	allShares := sss.sharePixels([]uint8{1, 2, 3, 4})
+
+	// Tests often run as root, which can write anywhere, so use paths that
+	// no permission bits allow: a missing directory and an existing directory
+	taken := filepath.Join(dir, "taken.png")
+	if err := os.Mkdir(taken, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, output := range []string{filepath.Join(dir, "missing", "out.png"), taken} {
+		// The shares describe 4 pixels, not 9; the path must fail first
+		_, err := sss.ReconstructImageToFile(allShares, 3, 3, ShareMetadata{}, output)
+		if err == nil || errors.Is(err, ErrDimensionMismatch) {
+			t.Errorf("%s: got %v, want the path to be rejected before reconstructing", output, err)
+		}
+	}
+
+	// The check leaves no file behind and does not truncate an existing one
+	fresh := filepath.Join(dir, "fresh.png")
+	if err := checkWritable(fresh); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(fresh); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("checkWritable left %s behind: %v", fresh, err)
+	}
+	existing := writeTestFile(t, "existing.png", "keep me")
+	if err := checkWritable(existing); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep me" {
+		t.Fatalf("checkWritable changed an existing file to %q", data)
+	}
+}
@@ -0,0 +1,74 @@
+//go:build darwin
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Keychain service name under which shares are filed
+const keychainService = "shamir-secret-sharing"
+
+// KeychainBackend stores shares as generic passwords in the macOS Keychain.
+// It drives the system security tool, so no cgo is needed.
+type KeychainBackend struct {
+	Service string
+}
+
+func (b KeychainBackend) service() string {
+	if b.Service == "" {
+		return keychainService
+	}
+	return b.Service
+}
+
+// security runs the security tool, mapping its "item not found" exit status
+func (b KeychainBackend) security(label string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("security", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 44 {
+		return "", fmt.Errorf("%w: %q", ErrShareNotFound, label)
+	}
+	if err != nil {
+		return "", fmt.Errorf("security %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Store adds or replaces the share in the login keychain. The security tool
+// only accepts the value as an argument, so it is briefly visible to other
+// local processes that list command lines.
+func (b KeychainBackend) Store(label string, share Point) error {
+	_, err := b.security(label, "add-generic-password", "-U",
+		"-s", b.service(), "-a", label, "-w", encodeStoredShare(share))
+	return err
+}
+
+// Load reads a share written by Store
+func (b KeychainBackend) Load(label string) (Point, error) {
+	out, err := b.security(label, "find-generic-password", "-s", b.service(), "-a", label, "-w")
+	if err != nil {
+		return Point{}, err
+	}
+	return DecodeShareHex(strings.TrimSpace(out))
+}
+
+// Delete removes a stored share from the keychain
+func (b KeychainBackend) Delete(label string) error {
+	_, err := b.security(label, "delete-generic-password", "-s", b.service(), "-a", label)
+	return err
+}
+
+// DefaultBackend returns the Keychain on macOS
+func DefaultBackend() (Backend, error) {
+	return KeychainBackend{}, nil
+}
@@ -0,0 +1,18 @@
+//go:build !darwin
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultBackend returns a file backend in the user's config directory on
+// platforms without a Keychain
+func DefaultBackend() (Backend, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return FileBackend{Dir: filepath.Join(dir, "shamir-secret-sharing", "shares")}, nil
+}
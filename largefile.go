@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Number of input bytes stored in each chunk share file
+const largeFileChunkSize = 64 * 1024
+
+// Glob matching the chunk files written by ShareLargeFile
+const largeFileChunkPattern = "chunk_*.shares"
+
+// largeFileChunkName is the name of the chunk file with the given index.
+// Indices are zero-padded to six digits and grow wider beyond that.
+func largeFileChunkName(chunk int) string {
+	return fmt.Sprintf("chunk_%06d.shares", chunk)
+}
+
+// largeFileChunks lists the chunk files in outputDir in chunk order
+func largeFileChunks(outputDir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(outputDir, largeFileChunkPattern))
+	if err != nil {
+		return nil, err
+	}
+	return orderLargeFileChunks(paths)
+}
+
+// orderLargeFileChunks sorts chunk file paths by the index parsed from their
+// names. Past 999999 the index outgrows its padding, so lexical order is no
+// longer chunk order. A missing chunk is an error rather than a silent hole
+// in the output.
+func orderLargeFileChunks(paths []string) ([]string, error) {
+	type chunkFile struct {
+		index int
+		path  string
+	}
+	chunks := make([]chunkFile, 0, len(paths))
+	for _, path := range paths {
+		name := filepath.Base(path)
+		index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "chunk_"), ".shares"))
+		if err != nil || index < 0 || largeFileChunkName(index) != name {
+			return nil, fmt.Errorf("%s: not a chunk file name", path)
+		}
+		chunks = append(chunks, chunkFile{index, path})
+	}
+	slices.SortFunc(chunks, func(a, b chunkFile) int { return cmp.Compare(a.index, b.index) })
+
+	ordered := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		if chunk.index != i {
+			return nil, fmt.Errorf("%w: chunk %d is missing", ErrTruncatedShares, i)
+		}
+		ordered[i] = chunk.path
+	}
+	return ordered, nil
+}
+
+// ShareLargeFile shares a file too large to load into memory. The file is
+// memory-mapped where the platform allows and shared in fixed-size chunks,
+// each written to its own share file in outputDir.
+func ShareLargeFile(path string, threshold, numShares int, outputDir string) error {
+	if threshold > numShares {
+		return fmt.Errorf("threshold %d cannot be greater than number of shares %d", threshold, numShares)
+	}
+
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return err
+	}
+	defer unmap()
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return err
+	}
+
+	sss, err := NewShamirSecretSharing(threshold, numShares)
+	if err != nil {
+		return err
+	}
+	meta := ShareMetadata{Threshold: threshold, Prime: sss.Prime()}
+
+	for chunk, offset := 0, 0; offset < len(data); chunk, offset = chunk+1, offset+largeFileChunkSize {
+		end := min(offset+largeFileChunkSize, len(data))
+
+		allShares, err := sss.ShareArbitraryBytes(data[offset:end])
+		if err != nil {
+			return err
+		}
+
+		filename := filepath.Join(outputDir, largeFileChunkName(chunk))
+		if err := saveTextSharesMeta(allShares, meta, filename); err != nil {
+			return err
+		}
+		sss.reportProgress(end, len(data))
+	}
+
+	return nil
+}
+
+// ReconstructLargeFile streams the chunks written by ShareLargeFile back into
+// a single output file, holding only one chunk in memory at a time
+func ReconstructLargeFile(outputDir string, threshold int, outputPath string) error {
+	chunks, err := largeFileChunks(outputDir)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+
+	for _, chunk := range chunks {
+		allShares, err := loadTextShares(chunk)
+		if err != nil {
+			return fmt.Errorf("%s: %w", chunk, err)
+		}
+		if len(allShares) == 0 {
+			continue
+		}
+
+		sss, err := NewShamirSecretSharing(threshold, len(allShares[0]))
+		if err != nil {
+			return fmt.Errorf("%s: %w", chunk, err)
+		}
+		data, err := sss.ReconstructArbitraryBytes(allShares)
+		if err != nil {
+			return fmt.Errorf("%s: %w", chunk, err)
+		}
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Setting this environment variable runs TestLargeFileRoundTrip on 10 MB,
+// which takes minutes, instead of a few chunks
+const envLargeFileTest = "SSS_TEST_LARGE_FILE"
+
+func TestLargeFileRoundTrip(t *testing.T) {
+	// Several chunks, including a partial last one
+	size := 3*largeFileChunkSize + 1000
+	if os.Getenv(envLargeFileTest) != "" {
+		size = 10 << 20
+	}
+	dir := t.TempDir()
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(dir, "input.bin")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	shares := filepath.Join(dir, "shares")
+	if err := ShareLargeFile(input, 2, 3, shares); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "output.bin")
+	if err := ReconstructLargeFile(shares, 2, output); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := sha256.Sum256(data), sha256.Sum256(got); !bytes.Equal(want[:], have[:]) {
+		t.Fatalf("output hash %x, want %x (%d of %d bytes)", have, want, len(got), len(data))
+	}
+}
+
+func TestOrderLargeFileChunks(t *testing.T) {
+	// One chunk past the six-digit padding: chunk_1000000 sorts lexically
+	// before chunk_100001, but must come last
+	const count = 1000001
+	paths := make([]string, count)
+	for i := range paths {
+		paths[i] = filepath.Join("shares", largeFileChunkName(i))
+	}
+	lexical := slices.Clone(paths)
+	slices.Sort(lexical)
+	if slices.Equal(lexical, paths) {
+		t.Fatal("lexical order already matches chunk order; the test proves nothing")
+	}
+
+	ordered, err := orderLargeFileChunks(lexical)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range paths {
+		if ordered[i] != paths[i] {
+			t.Fatalf("position %d holds %s, want %s", i, ordered[i], paths[i])
+		}
+	}
+
+	for _, tc := range []struct {
+		name  string
+		paths []string
+	}{
+		{"gap", []string{largeFileChunkName(0), largeFileChunkName(2)}},
+		{"no chunk 0", []string{largeFileChunkName(1)}},
+	} {
+		if _, err := orderLargeFileChunks(tc.paths); !errors.Is(err, ErrTruncatedShares) {
+			t.Errorf("%s: got %v, want ErrTruncatedShares", tc.name, err)
+		}
+	}
+	for _, name := range []string{"chunk_1.shares", "chunk_x.shares", "chunk_-00001.shares"} {
+		if _, err := orderLargeFileChunks([]string{name}); err == nil {
+			t.Errorf("%s accepted as a chunk file", name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// LayerShares holds the shares of one resolution level of an image
+type LayerShares struct {
+	Level  int // 0 is the coarsest level
+	Factor int // downsampling factor relative to the original image
+	Width  int
+	Height int
+	Shares [][]Point
+}
+
+// ShareImageLayers shares an image at several resolutions, from a coarse
+// preview downsampled by 2^(levels-1) up to the full-size image. Each level is
+// shared independently so holders can reconstruct a preview first.
+func (sss *ShamirSecretSharing) ShareImageLayers(imagePath string, levels int) ([]LayerShares, error) {
+	if levels < 1 {
+		return nil, errors.New("at least one resolution level is required")
+	}
+
+	pixels, width, height, err := loadGrayPixels(imagePath, sss.conversion)
+	if err != nil {
+		return nil, err
+	}
+
+	layers := make([]LayerShares, levels)
+	for level := 0; level < levels; level++ {
+		factor := 1 << (levels - 1 - level)
+		scaled, w, h := downsampleGray(pixels, width, height, factor)
+
+		layers[level] = LayerShares{
+			Level:  level,
+			Factor: factor,
+			Width:  w,
+			Height: h,
+			Shares: sss.sharePixels(scaled),
+		}
+	}
+
+	return layers, nil
+}
+
+// ReconstructLayer reconstructs a single resolution level, checking its
+// shares as ReconstructImage does
+func (sss *ShamirSecretSharing) ReconstructLayer(layerShares LayerShares) (image.Image, error) {
+	img, err := sss.ReconstructImage(layerShares.Shares, layerShares.Width, layerShares.Height)
+	if err != nil {
+		return nil, fmt.Errorf("layer %d: %w", layerShares.Level, err)
+	}
+	return img, nil
+}
+
+// downsampleGray averages factor x factor blocks of a grayscale image.
+// Blocks on the right and bottom edges may be partial.
+func downsampleGray(pixels []uint8, width, height, factor int) ([]uint8, int, int) {
+	if factor == 1 {
+		return pixels, width, height
+	}
+
+	w := (width + factor - 1) / factor
+	h := (height + factor - 1) / factor
+	scaled := make([]uint8, w*h)
+
+	for by := 0; by < h; by++ {
+		for bx := 0; bx < w; bx++ {
+			sum, count := 0, 0
+			for y := by * factor; y < (by+1)*factor && y < height; y++ {
+				for x := bx * factor; x < (bx+1)*factor && x < width; x++ {
+					sum += int(pixels[y*width+x])
+					count++
+				}
+			}
+			scaled[by*w+bx] = uint8(sum / count)
+		}
+	}
+
+	return scaled, w, h
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math/big"
+	"testing"
+)
+
+func TestShareImageLayers(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			src.SetGray(x, y, color.Gray{Y: uint8(x*4 + y/16)})
+		}
+	}
+	sss := newTestSharing(t, 2, 3)
+	layers, err := sss.ShareImageLayers(writeTestPNG(t, src), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(layers) != 3 {
+		t.Fatalf("got %d layers, want 3", len(layers))
+	}
+
+	pixels, _, _ := grayPixels(src, nil)
+	for level, want := range []struct{ factor, size int }{{4, 16}, {2, 32}, {1, 64}} {
+		layer := layers[level]
+		if layer.Level != level || layer.Factor != want.factor || layer.Width != want.size || layer.Height != want.size {
+			t.Fatalf("layer %d: level %d factor %d size %dx%d, want factor %d size %d",
+				level, layer.Level, layer.Factor, layer.Width, layer.Height, want.factor, want.size)
+		}
+
+		img, err := sss.ReconstructLayer(layer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _, _ := downsampleGray(pixels, 64, 64, want.factor)
+		got, _, _ := grayPixels(img, nil)
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("layer %d pixel %d = %d, want %d", level, i, got[i], expected[i])
+			}
+		}
+	}
+}
+
+func TestReconstructLayerRejectsBadShares(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	layer := LayerShares{Level: 1, Width: 2, Height: 2, Shares: make([][]Point, 4)}
+	for i := range layer.Shares {
+		layer.Shares[i] = sss.GenerateShares(big.NewInt(int64(i)))
+	}
+
+	layer.Shares[2] = layer.Shares[2][:1]
+	if _, err := sss.ReconstructLayer(layer); !errors.Is(err, ErrInsufficientShares) {
+		t.Errorf("too few shares: got %v, want ErrInsufficientShares", err)
+	}
+
+	layer.Shares = layer.Shares[:3]
+	if _, err := sss.ReconstructLayer(layer); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("missing pixel: got %v, want ErrDimensionMismatch", err)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTextLinesRoundTrip(t *testing.T) {
+	var hundred []string
+	for i := range 100 {
+		hundred = append(hundred, fmt.Sprintf("line %d of the log", i+1))
+	}
+
+	sss := newTestSharing(t, 2, 3)
+	for _, tc := range []struct {
+		name  string
+		text  string
+		lines int
+	}{
+		{"empty", "", 1},
+		{"single line", "just one", 1},
+		{"empty lines", "\n\nmiddle\n\n", 5},
+		{"trailing newline", "a\nb\n", 3},
+		{"100 lines", strings.Join(hundred, "\n"), 100},
+	} {
+		lineShares, err := sss.ShareTextLines(tc.text)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(lineShares) != tc.lines {
+			t.Fatalf("%s: %d lines, want %d", tc.name, len(lineShares), tc.lines)
+		}
+		for i, ls := range lineShares {
+			if ls.LineNumber != i+1 {
+				t.Fatalf("%s: line %d numbered %d", tc.name, i, ls.LineNumber)
+			}
+		}
+		if got, err := sss.ReconstructTextLines(lineShares); err != nil || got != tc.text {
+			t.Fatalf("%s: got %q, %v", tc.name, got, err)
+		}
+	}
+}
+
+func TestReconstructSelectedLines(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	lineShares, err := sss.ShareTextLines("public\nredacted\npublic too")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := sss.ReconstructTextLines([]LineShares{lineShares[0], lineShares[2]})
+	if err != nil || got != "public\npublic too" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+
+	// Errors name the line
+	lineShares[1].Shares[0] = lineShares[1].Shares[0][:1]
+	if _, err := sss.ReconstructTextLines(lineShares); err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Fatalf("got %v, want an error for line 2", err)
+	}
+}
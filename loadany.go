@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadSharesAny loads share files in mixed formats and merges them per
+// secret, for recoveries where participants kept their shares differently.
+// Formats are chosen by extension, then by content:
+//
+//   - .gob: SaveTextSharesGob output
+//   - .csv: rows of "secret,x,y" with an optional header row
+//   - .json, or content starting with '{': the /share response, {"shares": [[{"x", "y"}]]}
+//   - anything else: the text share file format
+//
+// Every file must cover the same number of secrets, and a participant
+// appearing in several files must have identical shares in each.
+func LoadSharesAny(paths []string) ([][]Point, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no share files to load")
+	}
+
+	var merged [][]Point
+	for n, path := range paths {
+		allShares, err := loadSharesSniffed(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		if n == 0 {
+			merged = make([][]Point, len(allShares))
+		} else if len(allShares) != len(merged) {
+			return nil, fmt.Errorf("%s: has %d secrets, expected %d like %s",
+				path, len(allShares), len(merged), paths[0])
+		}
+
+		for i, shares := range allShares {
+			if merged[i], err = mergeSecretShares(merged[i], shares); err != nil {
+				return nil, fmt.Errorf("%s: secret %d: %w", path, i, err)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// mergeSecretShares adds shares to existing, skipping exact duplicates and
+// rejecting different values for the same x-coordinate
+func mergeSecretShares(existing, shares []Point) ([]Point, error) {
+	for _, share := range shares {
+		duplicate := false
+		for _, have := range existing {
+			if have.X.Cmp(share.X) != 0 {
+				continue
+			}
+			if have.Y.Cmp(share.Y) != 0 {
+				return nil, fmt.Errorf("%w: x = %s", ErrInconsistentShares, share.X)
+			}
+			duplicate = true
+			break
+		}
+		if !duplicate {
+			existing = append(existing, share)
+		}
+	}
+	return existing, nil
+}
+
+// loadSharesSniffed loads one file in whichever format it appears to use
+func loadSharesSniffed(path string) ([][]Point, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gob":
+		allShares, _, err := LoadTextSharesGob(path)
+		return allShares, err
+	case ".csv":
+		return loadCSVShares(path)
+	case ".json":
+		return loadJSONShares(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	start, _ := reader.Peek(64)
+	if bytes.HasPrefix(bytes.TrimLeft(start, " \t\r\n"), []byte("{")) {
+		return decodeJSONShares(reader)
+	}
+	allShares, _, _, err := readTextShares(reader)
+	return allShares, err
+}
+
+// loadJSONShares reads shares saved from the /share endpoint's response
+func loadJSONShares(path string) ([][]Point, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return decodeJSONShares(file)
+}
+
+func decodeJSONShares(r io.Reader) ([][]Point, error) {
+	var saved shareResponse
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return nil, err
+	}
+	return parseJSONShares(saved.Shares)
+}
+
+// loadCSVShares reads "secret,x,y" rows; secrets are 0-based and may appear
+// in any order, but none may be skipped
+func loadCSVShares(path string) ([][]Point, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	var allShares [][]Point
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row == 1 && record[0] == "secret" {
+			continue
+		}
+
+		secret, err := strconv.Atoi(record[0])
+		x, okX := new(big.Int).SetString(record[1], 10)
+		y, okY := new(big.Int).SetString(record[2], 10)
+		if err != nil || secret < 0 || !okX || !okY || x.Sign() <= 0 {
+			return nil, fmt.Errorf("row %d: invalid share %q", row, strings.Join(record, ","))
+		}
+		for len(allShares) <= secret {
+			allShares = append(allShares, nil)
+		}
+		allShares[secret] = append(allShares[secret], Point{X: x, Y: y})
+	}
+
+	for i, shares := range allShares {
+		if len(shares) == 0 {
+			return nil, fmt.Errorf("no shares for secret %d", i)
+		}
+	}
+	return allShares, nil
+}
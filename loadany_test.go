@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSharesAnyMixedFormats(t *testing.T) {
+	dir := t.TempDir()
+	sss := newTestSharing(t, 2, 3)
+	allShares := mustShareText(t, sss, "mixed formats")
+
+	// Participant 1 kept the /share JSON response, participant 3 a text file
+	data, err := json.Marshal(shareResponse{Shares: formatJSONShares(subsetShares(allShares, 0))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(dir, "alice.json")
+	if err := os.WriteFile(jsonPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	textPath := filepath.Join(dir, "carol.txt")
+	if err := saveTextShares(subsetShares(allShares, 2), textPath); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := LoadSharesAny([]string{jsonPath, textPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := sss.ReconstructText(merged); err != nil || text != "mixed formats" {
+		t.Fatalf("got %q, %v; want \"mixed formats\"", text, err)
+	}
+
+	// JSON content under an unknown extension is sniffed too
+	sniffPath := filepath.Join(dir, "alice.share")
+	if err := os.WriteFile(sniffPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if merged, err = LoadSharesAny([]string{textPath, sniffPath}); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := sss.ReconstructText(merged); err != nil || text != "mixed formats" {
+		t.Fatalf("sniffed JSON: got %q, %v; want \"mixed formats\"", text, err)
+	}
+
+	// The same participant from a different split disagrees
+	otherPath := filepath.Join(dir, "other.txt")
+	if err := saveTextShares(subsetShares(mustShareText(t, sss, "other message"), 0), otherPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSharesAny([]string{jsonPath, otherPath}); !errors.Is(err, ErrInconsistentShares) {
+		t.Fatalf("shares of another message: got %v, want ErrInconsistentShares", err)
+	}
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDigestMismatch is returned when a reconstructed message fails its digest check
+var ErrDigestMismatch = errors.New("reconstructed message does not match stored digest")
+
+// ErrNonceMismatch is returned when a share file belongs to a different session
+var ErrNonceMismatch = errors.New("share file nonce does not match this session")
+
+// Version of the share file tooling recorded in every header
+const Version = "1.1.0"
+
+// Size in bytes of the per-operation session nonce
+const nonceSize = 32
+
+// Small prime, distinct from PRIME, used to reduce message digests
+var revealPrime = big.NewInt(65521)
+
+// ShareMetadata holds the optional header fields of a share file
+type ShareMetadata struct {
+	// Digest is H(message) mod revealPrime; nil when not recorded. It can be
+	// checked against guesses of the message, see MessageDigest.
+	Digest *big.Int
+
+	// Nonce identifies the sharing session; nil when not recorded
+	Nonce []byte
+
+	// Description is a free-text note; it may contain any UTF-8, including newlines
+	Description string
+
+	// Threshold is the number of shares needed to reconstruct; 0 when unknown
+	Threshold int
+
+	// XOffset is added to every participant's x-coordinate; 0 when unset
+	XOffset int
+
+	// Depth is the bits per pixel of an image share file; 0 means 8
+	Depth int
+
+	// Palette holds the colors of a palette image, whose shares are palette
+	// indices; nil for grayscale images
+	Palette color.Palette
+
+	// Format is the encoding of the source image, such as "png" or "jpeg",
+	// used by default when writing the reconstruction; empty when unknown
+	Format string
+
+	// Scheme is how the secrets were split; files without a scheme header
+	// hold polynomial shares
+	Scheme Scheme
+
+	// Prime is the field the shares belong to; nil when not recorded, as in
+	// files written before it was, which use the 2^31-1 default
+	Prime *big.Int
+
+	// Created and Version are stamped when a file is first saved
+	Created time.Time
+	Version string
+}
+
+// MessageDigest hashes a whole message and reduces it modulo revealPrime.
+//
+// The digest is unkeyed, so it leaks the message to guessing: anyone who
+// sees it can test candidate messages, and a PIN or short password falls to
+// a quick search. Only store it where every share is already present, such
+// as the dealer's combined share file, never in a file handed to a single
+// holder (see holderMetadata).
+func MessageDigest(data []byte) *big.Int {
+	sum := sha256.Sum256(data)
+	digest := new(big.Int).SetBytes(sum[:])
+	return digest.Mod(digest, revealPrime)
+}
+
+// VerifyMessageDigest checks a reconstructed message against a stored digest
+func VerifyMessageDigest(data []byte, digest *big.Int) error {
+	if MessageDigest(data).Cmp(digest) != 0 {
+		return ErrDigestMismatch
+	}
+	return nil
+}
+
+// writeShareHeader writes the "#key value" lines that precede the share data
+func writeShareHeader(w io.Writer, meta ShareMetadata) {
+	// Keep existing stamps so rewritten files still describe their origin
+	created := meta.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	version := meta.Version
+	if version == "" {
+		version = Version
+	}
+	fmt.Fprintf(w, "#created %s\n", created.UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "#version %s\n", version)
+
+	if meta.Digest != nil {
+		fmt.Fprintf(w, "#digest %s\n", meta.Digest.String())
+	}
+	if meta.Nonce != nil {
+		fmt.Fprintf(w, "#nonce %s\n", hex.EncodeToString(meta.Nonce))
+	}
+	if meta.Threshold != 0 {
+		fmt.Fprintf(w, "#threshold %d\n", meta.Threshold)
+	}
+	if meta.XOffset != 0 {
+		fmt.Fprintf(w, "#xoffset %d\n", meta.XOffset)
+	}
+	if meta.Depth != 0 {
+		fmt.Fprintf(w, "#depth %d\n", meta.Depth)
+	}
+	if meta.Palette != nil {
+		fmt.Fprintf(w, "#palette %s\n", formatPalette(meta.Palette))
+	}
+	if meta.Format != "" {
+		fmt.Fprintf(w, "#format %s\n", meta.Format)
+	}
+	if meta.Scheme != SchemePolynomial {
+		fmt.Fprintf(w, "#scheme %s\n", meta.Scheme)
+	}
+	if meta.Prime != nil {
+		fmt.Fprintf(w, "#prime %s\n", meta.Prime)
+	}
+	if meta.Description != "" {
+		// Quoting keeps newlines and other special characters on one line
+		fmt.Fprintf(w, "#description %s\n", strconv.Quote(meta.Description))
+	}
+}
+
+// scanShareHeader consumes header lines, leaving the scanner on the first data line
+func scanShareHeader(scanner *bufio.Scanner) (ShareMetadata, error) {
+	var meta ShareMetadata
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "#") {
+			return meta, nil
+		}
+
+		key, value, _ := strings.Cut(line[1:], " ")
+		switch key {
+		case "digest":
+			digest, ok := new(big.Int).SetString(value, 10)
+			if !ok {
+				return meta, fmt.Errorf("invalid digest %q", value)
+			}
+			meta.Digest = digest
+		case "nonce":
+			nonce, err := hex.DecodeString(value)
+			if err != nil {
+				return meta, fmt.Errorf("invalid nonce %q", value)
+			}
+			meta.Nonce = nonce
+		case "description":
+			description, err := strconv.Unquote(value)
+			if err != nil {
+				return meta, fmt.Errorf("invalid description %q", value)
+			}
+			meta.Description = description
+		case "created":
+			created, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return meta, fmt.Errorf("invalid creation time %q", value)
+			}
+			meta.Created = created
+		case "version":
+			meta.Version = value
+		case "threshold":
+			threshold, err := strconv.Atoi(value)
+			if err != nil {
+				return meta, fmt.Errorf("invalid threshold %q", value)
+			}
+			meta.Threshold = threshold
+		case "xoffset":
+			offset, err := strconv.Atoi(value)
+			if err != nil {
+				return meta, fmt.Errorf("invalid x offset %q", value)
+			}
+			meta.XOffset = offset
+		case "depth":
+			depth, err := strconv.Atoi(value)
+			if err != nil {
+				return meta, fmt.Errorf("invalid depth %q", value)
+			}
+			meta.Depth = depth
+		case "palette":
+			palette, err := parsePalette(value)
+			if err != nil {
+				return meta, err
+			}
+			meta.Palette = palette
+		case "format":
+			meta.Format = value
+		case "scheme":
+			scheme, err := ParseScheme(value)
+			if err != nil {
+				return meta, err
+			}
+			meta.Scheme = scheme
+		case "prime":
+			prime, ok := new(big.Int).SetString(value, 10)
+			if !ok || !prime.ProbablyPrime(32) {
+				return meta, fmt.Errorf("%w: %q", ErrInvalidPrime, value)
+			}
+			meta.Prime = prime
+		}
+		// Unknown keys are skipped so newer files remain readable
+	}
+
+	return meta, scanStopped(scanner, "missing share data")
+}
+
+// holderMetadata is meta as written to a file handed to a single holder.
+// The digest is dropped: it is computed from the plaintext, so one holder
+// could test guesses of a low-entropy secret against it alone.
+func holderMetadata(meta ShareMetadata) ShareMetadata {
+	meta.Digest = nil
+	return meta
+}
+
+// sharePrime returns the field meta records, or Prime31 for files that
+// predate the prime header
+func sharePrime(meta ShareMetadata) *big.Int {
+	if meta.Prime == nil {
+		return Prime31
+	}
+	return meta.Prime
+}
+
+// applyShareMetadata configures sss to reconstruct shares described by meta,
+// switching to the field the shares were made in
+func (sss *ShamirSecretSharing) applyShareMetadata(meta ShareMetadata) error {
+	if err := sss.SetPrime(sharePrime(meta)); err != nil {
+		return err
+	}
+	return sss.SetScheme(meta.Scheme)
+}
+
+// readShareHeader reads only the metadata header of a text or image share file
+func readShareHeader(filename string) (ShareMetadata, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return ShareMetadata{}, err
+	}
+	defer file.Close()
+
+	return scanShareHeader(newShareScanner(file))
+}
+
+// printShareMetadata writes the metadata fields in a human-readable form
+func printShareMetadata(w io.Writer, meta ShareMetadata) {
+	if !meta.Created.IsZero() {
+		fmt.Fprintf(w, "Created: %s\n", meta.Created.Format(time.RFC3339))
+	}
+	if meta.Version != "" {
+		fmt.Fprintf(w, "Version: %s\n", meta.Version)
+	}
+	if meta.Threshold != 0 {
+		fmt.Fprintf(w, "Threshold: %d\n", meta.Threshold)
+	}
+	if meta.XOffset != 0 {
+		fmt.Fprintf(w, "X offset: %d\n", meta.XOffset)
+	}
+	if meta.Depth != 0 {
+		fmt.Fprintf(w, "Bit depth: %d\n", meta.Depth)
+	}
+	if meta.Palette != nil {
+		fmt.Fprintf(w, "Palette: %d colors\n", len(meta.Palette))
+	}
+	if meta.Format != "" {
+		fmt.Fprintf(w, "Source format: %s\n", meta.Format)
+	}
+	if meta.Scheme != SchemePolynomial {
+		fmt.Fprintf(w, "Sharing scheme: %s\n", meta.Scheme)
+	}
+	if meta.Prime != nil {
+		fmt.Fprintf(w, "Field prime: %s\n", meta.Prime)
+	}
+	if meta.Description != "" {
+		fmt.Fprintf(w, "Description: %s\n", meta.Description)
+	}
+	if meta.Nonce != nil {
+		fmt.Fprintf(w, "Session nonce: %s\n", hex.EncodeToString(meta.Nonce))
+	}
+	if meta.Digest != nil {
+		fmt.Fprintf(w, "Message digest: %s (mod %s)\n", meta.Digest, revealPrime)
+	}
+}
+
+// formatPalette writes palette colors as comma-separated non-premultiplied
+// RRGGBBAA hex values
+func formatPalette(palette color.Palette) string {
+	entries := make([]string, len(palette))
+	for i, c := range palette {
+		n := color.NRGBAModel.Convert(c).(color.NRGBA)
+		entries[i] = fmt.Sprintf("%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+	}
+	return strings.Join(entries, ",")
+}
+
+// parsePalette reverses formatPalette
+func parsePalette(value string) (color.Palette, error) {
+	entries := strings.Split(value, ",")
+	if len(entries) > 256 {
+		return nil, fmt.Errorf("invalid palette: %d colors", len(entries))
+	}
+	palette := make(color.Palette, len(entries))
+	for i, entry := range entries {
+		b, err := hex.DecodeString(entry)
+		if err != nil || len(b) != 4 {
+			return nil, fmt.Errorf("invalid palette color %q", entry)
+		}
+		palette[i] = color.NRGBA{R: b[0], G: b[1], B: b[2], A: b[3]}
+	}
+	return palette, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMessageDigestDetectsTamperedShare(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	message := "attack at dawn"
+	digest := MessageDigest([]byte(message))
+	allShares, err := sss.ShareText(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reconstructed, err := sss.ReconstructArbitraryBytes(allShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyMessageDigest(reconstructed, digest); err != nil {
+		t.Fatalf("untampered shares: %v", err)
+	}
+
+	// With shares at x = 1 and 2 the secret is 2*y1 - y2, so adding one to
+	// y2 lowers the first byte by one
+	tampered := allShares[0][1]
+	allShares[0] = []Point{allShares[0][0], {X: tampered.X, Y: new(big.Int).Add(tampered.Y, big.NewInt(1))}}
+	reconstructed, err = sss.ReconstructArbitraryBytes(allShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(reconstructed) == message {
+		t.Fatal("tampering did not change the message")
+	}
+	if err := VerifyMessageDigest(reconstructed, digest); !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("tampered shares: got %v, want ErrDigestMismatch", err)
+	}
+}
+
+func TestPrimeRecordedInHeader(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if err := sss.SetPrime(Prime127); err != nil {
+		t.Fatal(err)
+	}
+	path := saveTestTextShares(t, sss, "wide", ShareMetadata{Prime: sss.Prime()}, t.TempDir(), "wide.txt")
+
+	allShares, meta, err := loadTextSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Prime == nil || meta.Prime.Cmp(Prime127) != 0 {
+		t.Fatalf("header prime = %v, want %s", meta.Prime, Prime127)
+	}
+	if err := ValidateTextShareFile(path, Prime31); err != nil {
+		t.Fatalf("ValidateTextShareFile with the default fallback: %v", err)
+	}
+
+	// A reader on the default field switches to the recorded one
+	reader := newTestSharing(t, 2, 3)
+	if err := reader.applyShareMetadata(meta); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := reader.ReconstructText(allShares); err != nil || text != "wide" {
+		t.Fatalf("got %q, %v; want \"wide\"", text, err)
+	}
+
+	decoded, err := unmarshalMetadataProto(marshalMetadataProto(meta))
+	if err != nil || decoded.Prime == nil || decoded.Prime.Cmp(Prime127) != 0 {
+		t.Fatalf("proto round trip: prime %v, %v; want %s", decoded.Prime, err, Prime127)
+	}
+}
+
+func TestMissingPrimeHeaderMeansDefault(t *testing.T) {
+	reader := newTestSharing(t, 2, 3)
+	if err := reader.SetPrime(Prime61); err != nil {
+		t.Fatal(err)
+	}
+	if err := reader.applyShareMetadata(ShareMetadata{}); err != nil {
+		t.Fatal(err)
+	}
+	if reader.Prime().Cmp(Prime31) != 0 {
+		t.Fatalf("prime = %s for a file without a prime header, want %s", reader.Prime(), Prime31)
+	}
+
+	path := writeTestFile(t, "bad.txt", "#prime 91\n1\n2\n1 5\n2 9\n")
+	if _, _, err := loadTextSharesMeta(path); !errors.Is(err, ErrInvalidPrime) {
+		t.Fatalf("composite #prime: got %v, want ErrInvalidPrime", err)
+	}
+}
+
+func TestShareTextWithNonce(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	allShares, nonce, err := sss.ShareTextWithNonce("session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := sss.ShareTextWithNonce("session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nonce) == 0 || bytes.Equal(nonce, other) {
+		t.Fatalf("nonces %x and %x should be non-empty and distinct", nonce, other)
+	}
+
+	// The nonce survives a save and load
+	path := filepath.Join(t.TempDir(), "nonce.txt")
+	if err := saveTextSharesMeta(allShares, ShareMetadata{Nonce: nonce}, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, meta, err := loadTextSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if text, err := sss.ReconstructTextVerified(loaded, meta.Nonce, nonce); err != nil || text != "session" {
+		t.Errorf("matching nonce: got %q, %v", text, err)
+	}
+	for name, expected := range map[string][]byte{"other session": other, "no nonce": nil} {
+		if _, err := sss.ReconstructTextVerified(loaded, meta.Nonce, expected); !errors.Is(err, ErrNonceMismatch) {
+			t.Errorf("%s: got %v, want ErrNonceMismatch", name, err)
+		}
+	}
+}
+
+func TestMultilineDescriptionRoundTrip(t *testing.T) {
+	description := "Board recovery key\nHolders: Ana, Bo\n\t\"quoted\" and unicode: ключ #not-a-header"
+	sss := newTestSharing(t, 2, 3)
+	path := saveTestTextShares(t, sss, "key", ShareMetadata{Description: description}, t.TempDir(), "described.txt")
+
+	allShares, meta, err := loadTextSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Description != description {
+		t.Fatalf("description = %q, want %q", meta.Description, description)
+	}
+	if text, err := sss.ReconstructText(allShares); err != nil || text != "key" {
+		t.Fatalf("got %q, %v; want \"key\"", text, err)
+	}
+}
+
+func TestHeaderRecordsCreationAndVersion(t *testing.T) {
+	before := time.Now().Add(-time.Second)
+	sss := newTestSharing(t, 2, 3)
+	path := saveTestTextShares(t, sss, "stamp", ShareMetadata{}, t.TempDir(), "stamped.txt")
+
+	_, meta, err := loadTextSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Version != Version {
+		t.Errorf("version = %q, want %q", meta.Version, Version)
+	}
+	if meta.Created.Before(before) || meta.Created.After(time.Now()) {
+		t.Errorf("created = %v, want about now", meta.Created)
+	}
+
+	// Rewriting a file keeps its original stamps
+	rewritten := filepath.Join(t.TempDir(), "rewritten.txt")
+	original := ShareMetadata{Created: time.Date(2020, 5, 6, 7, 8, 9, 0, time.UTC), Version: "0.1.0"}
+	if err := saveTextSharesMeta(nil, original, rewritten); err != nil {
+		t.Fatal(err)
+	}
+	if meta, err := readShareHeader(rewritten); err != nil || !meta.Created.Equal(original.Created) || meta.Version != "0.1.0" {
+		t.Errorf("rewritten header = %v, %q, %v; want the original stamps", meta.Created, meta.Version, err)
+	}
+}
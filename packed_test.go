@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTextPackedRoundTrip(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	for n := range 11 {
+		text := strings.Repeat("\x00ab\xff", 3)[:n]
+		allShares, err := sss.ShareTextPacked(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// A length secret plus one secret per started 3-byte chunk
+		if want := 1 + (n+2)/3; len(allShares) != want {
+			t.Errorf("%d bytes: %d secrets, want %d", n, len(allShares), want)
+		}
+		got, err := sss.ReconstructTextPacked(subsetShares(allShares, 4, 0, 2))
+		if err != nil || got != text {
+			t.Fatalf("%d bytes: got %q, %v; want %q", n, got, err, text)
+		}
+	}
+
+	// A wider field packs more bytes per secret
+	wide := newTestSharing(t, 2, 3)
+	if err := wide.SetPrime(Prime127); err != nil {
+		t.Fatal(err)
+	}
+	text := "fifteen bytes per chunk here"
+	allShares, err := wide.ShareTextPacked(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allShares) != 3 {
+		t.Errorf("%d bytes over Prime127: %d secrets, want 3", len(text), len(allShares))
+	}
+	if got, err := wide.ReconstructTextPacked(allShares); err != nil || got != text {
+		t.Fatalf("Prime127: got %q, %v", got, err)
+	}
+}
+
+func TestReconstructTextPackedMissingChunk(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	allShares, err := sss.ShareTextPacked("seven b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sss.ReconstructTextPacked(allShares[:len(allShares)-1]); !errors.Is(err, ErrTruncatedShares) {
+		t.Fatalf("dropped chunk: got %v, want ErrTruncatedShares", err)
+	}
+	if _, err := sss.ReconstructTextPacked(nil); !errors.Is(err, ErrTruncatedShares) {
+		t.Fatalf("no secrets: got %v, want ErrTruncatedShares", err)
+	}
+}
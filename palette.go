@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// ErrNotPaletted is returned by ShareImagePalette for images without a palette
+var ErrNotPaletted = errors.New("image is not palette-based")
+
+// ShareImagePalette shares a palette (indexed-color) image by its 8-bit
+// palette indices rather than converting it to grayscale. The palette is not
+// secret-shared and must be stored alongside the shares, as the palette
+// header of an image share file does.
+func (sss *ShamirSecretSharing) ShareImagePalette(imagePath string) ([][]Point, int, int, color.Palette, error) {
+	img, err := decodeImageFile(imagePath)
+	if err != nil {
+		return nil, 0, 0, nil, err
+	}
+	paletted, ok := img.(*image.Paletted)
+	if !ok {
+		return nil, 0, 0, nil, fmt.Errorf("%w: %s", ErrNotPaletted, imagePath)
+	}
+
+	bounds := paletted.Bounds()
+	return sss.sharePixels(palettedIndices(paletted)), bounds.Dx(), bounds.Dy(), paletted.Palette, nil
+}
+
+// ReconstructImagePalette rebuilds a palette image from ShareImagePalette
+// output; encoding the result as PNG produces an indexed-color PNG
+func (sss *ShamirSecretSharing) ReconstructImagePalette(allShares [][]Point, width, height int, palette color.Palette) (*image.Paletted, error) {
+	if len(palette) == 0 {
+		return nil, errors.New("palette is empty")
+	}
+	indices, err := sss.ReconstructImageBytes(allShares, width, height)
+	if err != nil {
+		return nil, err
+	}
+	for i, index := range indices {
+		if int(index) >= len(palette) {
+			return nil, fmt.Errorf("pixel %d has index %d outside a %d-color palette", i, index, len(palette))
+		}
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+	copy(img.Pix, indices)
+	return img, nil
+}
+
+// palettedIndices returns an image's palette indices in row-major order
+func palettedIndices(img *image.Paletted) []uint8 {
+	bounds := img.Bounds()
+	indices := make([]uint8, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		start := img.PixOffset(bounds.Min.X, y)
+		indices = append(indices, img.Pix[start:start+bounds.Dx()]...)
+	}
+	return indices
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"testing"
+)
+
+func TestImagePaletteRoundTrip(t *testing.T) {
+	palette := color.Palette{
+		color.NRGBA{R: 255, A: 255},
+		color.NRGBA{G: 255, A: 255},
+		color.NRGBA{B: 255, A: 255},
+		color.NRGBA{R: 30, G: 60, B: 90, A: 255},
+	}
+	src := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+	for i := range src.Pix {
+		src.Pix[i] = uint8((i * 7) % len(palette))
+	}
+
+	sss := newTestSharing(t, 2, 3)
+	allShares, width, height, gotPalette, err := sss.ShareImagePalette(writeTestPNG(t, src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if width != 4 || height != 4 || len(gotPalette) != len(palette) {
+		t.Fatalf("got %dx%d with %d colors, want 4x4 with 4", width, height, len(gotPalette))
+	}
+
+	// The palette travels in the share file header
+	path := filepath.Join(t.TempDir(), "palette_shares.txt")
+	if err := saveImageSharesMeta(subsetShares(allShares, 0, 2), width, height, ShareMetadata{Threshold: 2, Palette: gotPalette}, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, width, height, meta, err := loadImageSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := sss.ReconstructImagePalette(loaded, width, height, meta.Palette)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, ok := decoded.(*image.Paletted)
+	if !ok {
+		t.Fatalf("decoded a %T, want a palette PNG", decoded)
+	}
+	if !bytes.Equal(out.Pix, src.Pix) {
+		t.Fatalf("indices %v, want %v", out.Pix, src.Pix)
+	}
+	if len(out.Palette) != len(palette) {
+		t.Fatalf("got %d palette entries, want %d", len(out.Palette), len(palette))
+	}
+	for i, c := range palette {
+		if got := color.NRGBAModel.Convert(out.Palette[i]); got != c {
+			t.Errorf("palette entry %d: got %v, want %v", i, got, c)
+		}
+	}
+}
+
+func TestImagePaletteErrors(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if _, _, _, _, err := sss.ShareImagePalette(writeTestPNG(t, image.NewGray(image.Rect(0, 0, 2, 2)))); !errors.Is(err, ErrNotPaletted) {
+		t.Fatalf("grayscale PNG: got %v, want ErrNotPaletted", err)
+	}
+
+	// Index 3 has no entry in a 2-color palette
+	allShares := sss.sharePixels([]uint8{0, 1, 3, 1})
+	if _, err := sss.ReconstructImagePalette(allShares, 2, 2, color.Palette{color.Black, color.White}); err == nil {
+		t.Fatal("out-of-range index was accepted")
+	}
+	if _, err := sss.ReconstructImagePalette(allShares, 2, 2, nil); err == nil {
+		t.Fatal("empty palette was accepted")
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultImageChunkSize is the number of pixels each worker reconstructs at
+// a time in ReconstructImageParallel when no chunk size is given
+const DefaultImageChunkSize = 1000
+
+// ReconstructImageParallel is ReconstructImage spread across worker
+// goroutines (see SetConcurrency). Workers claim chunkSize consecutive
+// pixels at a time: larger chunks cost less coordination and keep each
+// worker writing to its own stretch of the image, smaller ones balance the
+// load better. A chunkSize of zero or less uses DefaultImageChunkSize.
+func (sss *ShamirSecretSharing) ReconstructImageParallel(allShares [][]Point, width, height, chunkSize int) (image.Image, error) {
+	if len(allShares) != width*height {
+		return nil, fmt.Errorf("%w: have shares for %d pixels, a %dx%d image has %d",
+			ErrDimensionMismatch, len(allShares), width, height, width*height)
+	}
+	allShares, err := sss.prepareShares(allShares)
+	if err != nil {
+		return nil, err
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultImageChunkSize
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	total := len(allShares)
+	chunks := (total + chunkSize - 1) / chunkSize
+
+	var next atomic.Int64
+	var progressMu sync.Mutex
+	done := 0
+
+	var wg sync.WaitGroup
+	for w := sss.workers(chunks); w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				chunk := int(next.Add(1) - 1)
+				if chunk >= chunks {
+					return
+				}
+				start := chunk * chunkSize
+				end := min(start+chunkSize, total)
+				// NewGray has Stride == width, so pixel i is Pix[i]
+				for i := start; i < end; i++ {
+					img.Pix[i] = uint8(sss.ReconstructSecret(allShares[i]).Int64())
+				}
+
+				// The callback need not be goroutine-safe, so calls are serialised
+				progressMu.Lock()
+				done += end - start
+				sss.reportProgress(done, total)
+				progressMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return img, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"runtime"
+	"testing"
+)
+
+func TestConcurrencyCap(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	for _, tc := range []struct {
+		concurrency, jobs, want int
+	}{
+		{1, 100, 1},
+		{2, 100, 2},
+		{2, 1, 1},
+		{0, 1000, min(runtime.NumCPU(), 1000)},
+		{-3, 0, 1},
+	} {
+		sss.SetConcurrency(tc.concurrency)
+		if got := sss.workers(tc.jobs); got != tc.want {
+			t.Errorf("concurrency %d, %d jobs: %d workers, want %d", tc.concurrency, tc.jobs, got, tc.want)
+		}
+	}
+
+	// Concurrency 1 runs in order on the calling goroutine
+	sss.SetConcurrency(1)
+	var order []int
+	sss.parallelFor(50, func(i int) { order = append(order, i) })
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("sequential order = %v", order)
+		}
+	}
+	if len(order) != 50 {
+		t.Fatalf("ran %d of 50 indices", len(order))
+	}
+}
+
+func TestConcurrencyResultsMatch(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 40, 30))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 13)
+	}
+	text := "workers must not change the result"
+
+	for _, concurrency := range []int{1, 2} {
+		sss := newTestSharing(t, 3, 5)
+		sss.SetConcurrency(concurrency)
+		allShares := sss.sharePixels(src.Pix)
+
+		img, err := sss.ReconstructImageParallel(allShares, 40, 30, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(img.(*image.Gray).Pix, src.Pix) {
+			t.Errorf("concurrency %d: parallel image differs", concurrency)
+		}
+
+		textShares, err := sss.ShareTextConcurrent(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := sss.ReconstructTextConcurrent(textShares); err != nil || got != text {
+			t.Errorf("concurrency %d: got %q, %v", concurrency, got, err)
+		}
+	}
+}
+
+// BenchmarkReconstructImageParallel compares chunk sizes on a 512x512 image;
+// "sequential" is ReconstructImage for reference
+func BenchmarkReconstructImageParallel(b *testing.B) {
+	const width, height = 512, 512
+	sss := newTestSharing(b, 2, 3)
+	pixels := make([]uint8, width*height)
+	for i := range pixels {
+		pixels[i] = uint8(i)
+	}
+	allShares := subsetShares(sss.sharePixels(pixels), 0, 2)
+
+	b.Run("sequential", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := sss.ReconstructImage(allShares, width, height); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, chunkSize := range []int{100, 500, 1000, 5000} {
+		b.Run(fmt.Sprintf("chunk=%d", chunkSize), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := sss.ReconstructImageParallel(allShares, width, height, chunkSize); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
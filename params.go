@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrTooFewShares is returned when fewer shares are requested than the
+// configured minimum and the caller has not forced it
+var ErrTooFewShares = errors.New("too few shares requested")
+
+// DefaultMinShares is the smallest share count accepted without forcing;
+// a single share is the secret in all but name
+const DefaultMinShares = 2
+
+// Warnings reported by AnalyzeParameters
+const (
+	WarnInvalidThreshold = "threshold must be between 1 and the number of shares"
+	WarnThresholdOne     = "threshold 1 reveals the secret: every share is the secret itself"
+	WarnNoRedundancy     = "no redundancy: every share is required, so losing any one share loses the secret"
+	WarnNotPrime         = "modulus is not prime, so shares may not determine the secret"
+	WarnSmallPrime       = "prime smaller than secret space: byte values of 256 and above would wrap"
+	WarnTooManyShares    = "prime too small for this many shares: x-coordinates would repeat"
+)
+
+// ParameterAnalysis describes the strength of a threshold configuration
+type ParameterAnalysis struct {
+	Threshold int
+	NumShares int
+
+	// Tolerance is how many shares may be lost with the secret still recoverable
+	Tolerance int
+
+	// Warnings lists the weaknesses found; empty for a sound configuration
+	Warnings []string
+}
+
+// String summarises the analysis on one line
+func (a ParameterAnalysis) String() string {
+	return fmt.Sprintf("%d-of-%d, tolerates %d lost shares, %d warnings",
+		a.Threshold, a.NumShares, a.Tolerance, len(a.Warnings))
+}
+
+// AnalyzeParameters checks a threshold, share count and prime for
+// configurations that are insecure or fragile. It never fails; problems are
+// reported as warnings so callers can decide whether to go ahead.
+func AnalyzeParameters(threshold, numShares int, prime *big.Int) ParameterAnalysis {
+	a := ParameterAnalysis{Threshold: threshold, NumShares: numShares}
+
+	if threshold < 1 || threshold > numShares {
+		a.Warnings = append(a.Warnings, WarnInvalidThreshold)
+	} else {
+		a.Tolerance = numShares - threshold
+		if threshold == 1 {
+			a.Warnings = append(a.Warnings, WarnThresholdOne)
+		}
+		if threshold == numShares {
+			a.Warnings = append(a.Warnings, WarnNoRedundancy)
+		}
+	}
+
+	if prime == nil || !prime.ProbablyPrime(20) {
+		a.Warnings = append(a.Warnings, WarnNotPrime)
+		return a
+	}
+	// Text and image secrets are single bytes
+	if prime.Cmp(big.NewInt(256)) <= 0 {
+		a.Warnings = append(a.Warnings, WarnSmallPrime)
+	}
+	if prime.Cmp(big.NewInt(int64(numShares))) <= 0 {
+		a.Warnings = append(a.Warnings, WarnTooManyShares)
+	}
+	return a
+}
+
+// checkMinShares guards against a mistyped share count: below minShares it
+// fails with ErrTooFewShares unless force is set
+func checkMinShares(numShares, minShares int, force bool) error {
+	if numShares < minShares && !force {
+		return fmt.Errorf("%w: %d is below the minimum of %d (use -force to allow it)", ErrTooFewShares, numShares, minShares)
+	}
+	return nil
+}
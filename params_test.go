@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func TestAnalyzeParameters(t *testing.T) {
+	tests := []struct {
+		name                 string
+		threshold, numShares int
+		prime                *big.Int
+		want                 []string
+	}{
+		{"strong", 3, 5, Prime31, nil},
+		{"strong large prime", 5, 9, Prime521, nil},
+		{"threshold one", 1, 3, Prime31, []string{WarnThresholdOne}},
+		{"no redundancy", 2, 2, Prime31, []string{WarnNoRedundancy}},
+		{"single share", 1, 1, Prime31, []string{WarnThresholdOne, WarnNoRedundancy}},
+		{"threshold above shares", 4, 3, Prime31, []string{WarnInvalidThreshold}},
+		{"zero threshold", 0, 3, Prime31, []string{WarnInvalidThreshold}},
+		{"composite modulus", 3, 5, big.NewInt(1 << 20), []string{WarnNotPrime}},
+		{"nil prime", 3, 5, nil, []string{WarnNotPrime}},
+		{"small prime", 3, 5, big.NewInt(251), []string{WarnSmallPrime}},
+		{"prime below share count", 2, 7, big.NewInt(5), []string{WarnSmallPrime, WarnTooManyShares}},
+	}
+	for _, tt := range tests {
+		a := AnalyzeParameters(tt.threshold, tt.numShares, tt.prime)
+		if !slices.Equal(a.Warnings, tt.want) {
+			t.Errorf("%s: warnings %q, want %q", tt.name, a.Warnings, tt.want)
+		}
+	}
+
+	if a := AnalyzeParameters(3, 5, Prime31); a.Tolerance != 2 {
+		t.Errorf("3-of-5 tolerates %d lost shares, want 2", a.Tolerance)
+	}
+}
+
+func TestCheckMinShares(t *testing.T) {
+	tests := []struct {
+		numShares, minShares int
+		force, wantErr       bool
+	}{
+		{1, DefaultMinShares, false, true},
+		{1, DefaultMinShares, true, false},
+		{2, DefaultMinShares, false, false},
+		{5, DefaultMinShares, false, false},
+		{3, 5, false, true},
+		{3, 5, true, false},
+	}
+	for _, tt := range tests {
+		err := checkMinShares(tt.numShares, tt.minShares, tt.force)
+		if tt.wantErr != errors.Is(err, ErrTooFewShares) || !tt.wantErr && err != nil {
+			t.Errorf("checkMinShares(%d, %d, %v) = %v, want error %v", tt.numShares, tt.minShares, tt.force, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Polynomial is a polynomial over the integers modulo Prime.
+// Coefficients are in ascending order, so Coefficients[0] is the constant term.
+type Polynomial struct {
+	Coefficients []*big.Int
+	Prime        *big.Int
+}
+
+// Evaluate returns the polynomial's value at x
+func (p *Polynomial) Evaluate(x *big.Int) *big.Int {
+	return evaluateCoefficients(p.Coefficients, x, p.Prime)
+}
+
+// Degree returns the index of the highest non-zero coefficient, or -1 for
+// the zero polynomial
+func (p *Polynomial) Degree() int {
+	for i := len(p.Coefficients) - 1; i >= 0; i-- {
+		if new(big.Int).Mod(p.Coefficients[i], p.Prime).Sign() != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Add returns the sum of two polynomials; both must use the same prime
+func (p *Polynomial) Add(other *Polynomial) *Polynomial {
+	n := max(len(p.Coefficients), len(other.Coefficients))
+	sum := make([]*big.Int, n)
+
+	for i := range sum {
+		sum[i] = big.NewInt(0)
+		if i < len(p.Coefficients) {
+			sum[i].Add(sum[i], p.Coefficients[i])
+		}
+		if i < len(other.Coefficients) {
+			sum[i].Add(sum[i], other.Coefficients[i])
+		}
+		sum[i].Mod(sum[i], p.Prime)
+	}
+
+	return &Polynomial{Coefficients: sum, Prime: p.Prime}
+}
+
+// Mul returns the polynomial multiplied by a scalar
+func (p *Polynomial) Mul(scalar *big.Int) *Polynomial {
+	product := make([]*big.Int, len(p.Coefficients))
+	for i, c := range p.Coefficients {
+		product[i] = new(big.Int).Mul(c, scalar)
+		product[i].Mod(product[i], p.Prime)
+	}
+
+	return &Polynomial{Coefficients: product, Prime: p.Prime}
+}
+
+// InterpolateFromPoints returns the unique polynomial of degree below
+// len(points) that passes through every point
+func InterpolateFromPoints(points []Point, prime *big.Int) (*Polynomial, error) {
+	if len(points) == 0 {
+		return nil, errors.New("at least one point is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range points {
+		x := new(big.Int).Mod(p.X, prime).String()
+		if seen[x] {
+			return nil, fmt.Errorf("duplicate x-coordinate %s", p.X)
+		}
+		seen[x] = true
+	}
+
+	result := make([]*big.Int, len(points))
+	for i := range result {
+		result[i] = big.NewInt(0)
+	}
+
+	for i, pi := range points {
+		// Build the basis polynomial prod_{j != i} (x - x_j) and its value at x_i
+		basis := []*big.Int{big.NewInt(1)}
+		denominator := big.NewInt(1)
+
+		for j, pj := range points {
+			if i == j {
+				continue
+			}
+			negXj := new(big.Int).Neg(pj.X)
+
+			next := make([]*big.Int, len(basis)+1)
+			next[0] = new(big.Int).Mul(basis[0], negXj)
+			for k := 1; k < len(basis); k++ {
+				next[k] = new(big.Int).Mul(basis[k], negXj)
+				next[k].Add(next[k], basis[k-1])
+			}
+			next[len(basis)] = new(big.Int).Set(basis[len(basis)-1])
+			for _, c := range next {
+				c.Mod(c, prime)
+			}
+			basis = next
+
+			denominator.Mul(denominator, new(big.Int).Sub(pi.X, pj.X))
+			denominator.Mod(denominator, prime)
+		}
+
+		scale := new(big.Int).Mul(pi.Y, modInverse(denominator, prime))
+		scale.Mod(scale, prime)
+
+		for k, c := range basis {
+			term := new(big.Int).Mul(c, scale)
+			result[k].Add(result[k], term)
+			result[k].Mod(result[k], prime)
+		}
+	}
+
+	return &Polynomial{Coefficients: result, Prime: prime}, nil
+}
+
+// GetPolynomial returns a fresh random sharing polynomial whose constant term
+// is the secret.
+//
+// This is security-sensitive: anyone holding the polynomial knows the secret
+// and every share. It is intended for teaching and for building custom schemes.
+func (sss *ShamirSecretSharing) GetPolynomial(secret *big.Int) (*Polynomial, error) {
+	if secret.Sign() < 0 || secret.Cmp(sss.prime) >= 0 {
+		return nil, fmt.Errorf("secret must be in [0, %s)", sss.prime)
+	}
+
+	return &Polynomial{
+		Coefficients: sss.generateRandomCoefficients(secret),
+		Prime:        sss.Prime(),
+	}, nil
+}
+
+// GeneratePolynomial is the first phase of a two-phase deal: it returns the
+// random sharing polynomial for secret without evaluating it, so the dealer
+// can publish commitments to its coefficients and wait for participants to
+// confirm before handing out shares with GenerateSharesFromPolynomial. For
+// thresholds above 1 the leading coefficient is never zero, so the degree
+// is exactly threshold-1.
+//
+// Instances using SchemeXOR are rejected, since their shares are an XOR
+// split rather than points on a polynomial.
+func (sss *ShamirSecretSharing) GeneratePolynomial(secret *big.Int) (*Polynomial, error) {
+	if sss.usesXOR() {
+		return nil, fmt.Errorf("%w: the XOR scheme does not use a polynomial", ErrInvalidThreshold)
+	}
+	poly, err := sss.GetPolynomial(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if last := len(poly.Coefficients) - 1; last > 0 {
+		leading, err := rand.Int(rand.Reader, new(big.Int).Sub(sss.prime, big.NewInt(1)))
+		if err != nil {
+			return nil, err
+		}
+		poly.Coefficients[last] = leading.Add(leading, big.NewInt(1))
+	}
+	return poly, nil
+}
+
+// GenerateSharesFromPolynomial is the second phase of a two-phase deal: it
+// evaluates a polynomial from GeneratePolynomial at x = 1..numShares, shifted
+// by the x offset as in GenerateShares
+func (sss *ShamirSecretSharing) GenerateSharesFromPolynomial(poly *Polynomial) ([]Point, error) {
+	if sss.usesXOR() {
+		return nil, fmt.Errorf("%w: the XOR scheme does not use a polynomial", ErrInvalidThreshold)
+	}
+	if poly.Prime.Cmp(sss.prime) != 0 {
+		return nil, fmt.Errorf("%w: polynomial is over %s, not %s", ErrInvalidPrime, poly.Prime, sss.prime)
+	}
+	if len(poly.Coefficients) > sss.threshold {
+		return nil, fmt.Errorf("%w: polynomial has %d coefficients for threshold %d", ErrInvalidThreshold, len(poly.Coefficients), sss.threshold)
+	}
+
+	shares := make([]Point, sss.numShares)
+	for i := range shares {
+		x := big.NewInt(int64(sss.xOffset + i + 1))
+		shares[i] = Point{X: x, Y: poly.Evaluate(x)}
+	}
+	return shares, nil
+}
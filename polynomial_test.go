@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestPolynomialEvaluateAndInterpolate(t *testing.T) {
+	prime := Prime31
+	// p(x) = 6 + 2x + x^2, q(x) = 1 + 5x
+	p := &Polynomial{Coefficients: []*big.Int{big.NewInt(6), big.NewInt(2), big.NewInt(1)}, Prime: prime}
+	q := &Polynomial{Coefficients: []*big.Int{big.NewInt(1), big.NewInt(5)}, Prime: prime}
+
+	for x, want := range map[int64]int64{0: 6, 1: 9, 2: 14, 3: 21} {
+		if got := p.Evaluate(big.NewInt(x)); got.Int64() != want {
+			t.Errorf("p(%d) = %s, want %d", x, got, want)
+		}
+	}
+	if got := p.Add(q).Evaluate(big.NewInt(2)); got.Int64() != 14+11 {
+		t.Errorf("(p+q)(2) = %s, want 25", got)
+	}
+	if got := p.Mul(big.NewInt(3)).Evaluate(big.NewInt(2)); got.Int64() != 42 {
+		t.Errorf("(3p)(2) = %s, want 42", got)
+	}
+	if p.Degree() != 2 || q.Degree() != 1 {
+		t.Errorf("degrees %d and %d, want 2 and 1", p.Degree(), q.Degree())
+	}
+
+	// Interpolating points of p gives back p's coefficients
+	points := make([]Point, 3)
+	for i := range points {
+		x := big.NewInt(int64(i + 4))
+		points[i] = Point{X: x, Y: p.Evaluate(x)}
+	}
+	interpolated, err := InterpolateFromPoints(points, prime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range p.Coefficients {
+		if interpolated.Coefficients[i].Cmp(c) != 0 {
+			t.Errorf("coefficient %d = %s, want %s", i, interpolated.Coefficients[i], c)
+		}
+	}
+
+	if _, err := InterpolateFromPoints([]Point{points[0], points[0]}, prime); err == nil {
+		t.Error("interpolated through a repeated x")
+	}
+}
+
+func TestInterpolateFromPointsMatchesShares(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	secret := big.NewInt(8675309)
+	shares := sss.GenerateShares(secret)
+	poly, err := InterpolateFromPoints(shares[:3], sss.prime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := poly.Evaluate(big.NewInt(0)); got.Cmp(secret) != 0 {
+		t.Errorf("p(0) = %s, want %s", got, secret)
+	}
+	// The shares left out lie on the same polynomial
+	for _, share := range shares[3:] {
+		if got := poly.Evaluate(share.X); got.Cmp(share.Y) != 0 {
+			t.Errorf("p(%s) = %s, want %s", share.X, got, share.Y)
+		}
+	}
+}
+
+func TestGeneratePolynomial(t *testing.T) {
+	secret := big.NewInt(424242)
+	for threshold := 1; threshold <= 5; threshold++ {
+		sss := newTestSharing(t, threshold, 5)
+		for range 20 {
+			poly, err := sss.GeneratePolynomial(secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := poly.Degree(); got != threshold-1 {
+				t.Fatalf("threshold %d: degree %d, want %d", threshold, got, threshold-1)
+			}
+			if got := poly.Evaluate(big.NewInt(0)); got.Cmp(secret) != 0 {
+				t.Fatalf("threshold %d: p(0) = %s, want %s", threshold, got, secret)
+			}
+		}
+	}
+
+	sss := newTestSharing(t, 2, 3)
+	if _, err := sss.GeneratePolynomial(Prime31); err == nil {
+		t.Error("secret equal to the prime was accepted")
+	}
+	xor := newTestSharing(t, 3, 3)
+	if err := xor.SetScheme(SchemeXOR); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := xor.GeneratePolynomial(secret); err == nil {
+		t.Error("XOR instance produced a polynomial")
+	}
+}
+
+func TestTwoPhaseDeal(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	sss.SetXOffset(10)
+	secret := big.NewInt(1234567)
+
+	poly, err := sss.GeneratePolynomial(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := sss.GenerateSharesFromPolynomial(poly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 5 || shares[0].X.Int64() != 11 || shares[4].X.Int64() != 15 {
+		t.Fatalf("got shares %v, want x = 11..15", shares)
+	}
+	for _, subset := range [][]Point{shares[:3], shares[2:], {shares[4], shares[0], shares[2]}} {
+		if got := sss.ReconstructSecret(subset); got.Cmp(secret) != 0 {
+			t.Fatalf("reconstructed %s, want %s", got, secret)
+		}
+	}
+
+	wide := &Polynomial{Coefficients: append(poly.Coefficients, big.NewInt(1)), Prime: poly.Prime}
+	if _, err := sss.GenerateSharesFromPolynomial(wide); !errors.Is(err, ErrInvalidThreshold) {
+		t.Fatalf("degree above threshold-1: got %v, want ErrInvalidThreshold", err)
+	}
+	other := &Polynomial{Coefficients: poly.Coefficients, Prime: Prime61}
+	if _, err := sss.GenerateSharesFromPolynomial(other); !errors.Is(err, ErrInvalidPrime) {
+		t.Fatalf("polynomial over another prime: got %v, want ErrInvalidPrime", err)
+	}
+}
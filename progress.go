@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Width of the textual progress bar in characters
+const progressBarWidth = 40
+
+// renderProgressBar formats a progress bar for a fraction in [0, 1]
+func renderProgressBar(fraction float64, width int) string {
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+
+	filled := int(fraction * float64(width))
+	bar := strings.Repeat("#", filled) + strings.Repeat(" ", width-filled)
+
+	return fmt.Sprintf("[%s] %3d%%", bar, int(fraction*100))
+}
+
+// newProgressPrinter returns a progress callback that redraws the bar in place
+func newProgressPrinter(w io.Writer) func(done, total int) {
+	last := -1
+
+	return func(done, total int) {
+		if total <= 0 {
+			return
+		}
+
+		// Only redraw when the percentage changes to keep the terminal responsive
+		percent := done * 100 / total
+		if percent == last {
+			return
+		}
+		last = percent
+
+		fmt.Fprintf(w, "\r%s", renderProgressBar(float64(done)/float64(total), progressBarWidth))
+		if done >= total {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// isTerminal reports whether the file is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderProgressBar(t *testing.T) {
+	for _, tc := range []struct {
+		fraction float64
+		want     string
+	}{
+		{0, "[          ]   0%"},
+		{0.25, "[##        ]  25%"},
+		{0.333, "[###       ]  33%"},
+		{0.999, "[######### ]  99%"},
+		{1, "[##########] 100%"},
+		{-0.5, "[          ]   0%"},
+		{1.5, "[##########] 100%"},
+	} {
+		if got := renderProgressBar(tc.fraction, 10); got != tc.want {
+			t.Errorf("renderProgressBar(%v, 10) = %q, want %q", tc.fraction, got, tc.want)
+		}
+	}
+}
+
+func TestProgressPrinterRedrawsOnPercentChange(t *testing.T) {
+	var out strings.Builder
+	progress := newProgressPrinter(&out)
+	for done := 0; done <= 400; done++ {
+		progress(done, 400)
+	}
+	if redraws := strings.Count(out.String(), "\r"); redraws != 101 {
+		t.Errorf("%d redraws for 0%%..100%%, want 101", redraws)
+	}
+	if !strings.HasSuffix(out.String(), "100%\n") {
+		t.Errorf("output does not end with a finished bar: %q", out.String()[max(0, out.Len()-60):])
+	}
+}
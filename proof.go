@@ -0,0 +1,106 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+)
+
+// ThresholdProof is a non-interactive proof of knowledge (Okamoto's variant
+// of Schnorr's) of the opening (x, r) of a Pedersen commitment
+// C = G^x * H^r mod P, such as the commitment to the secret that FeldmanVSS
+// publishes. It reveals nothing about x or r.
+type ThresholdProof struct {
+	// Commitment is the prover's nonce commitment T = G^k * H^l mod P
+	Commitment *big.Int
+	// Response is s = k + c*x mod Q for the Fiat-Shamir challenge c
+	Response *big.Int
+	// BlindingResponse is u = l + c*r mod Q
+	BlindingResponse *big.Int
+}
+
+// proofChallenge derives the Fiat-Shamir challenge from the whole statement
+// and the nonce commitment, so a proof cannot be moved to another statement
+func proofChallenge(group *PedersenCommitment, y, t *big.Int) *big.Int {
+	h := sha256.New()
+	h.Write([]byte("shamir threshold proof"))
+	for _, v := range []*big.Int{group.P, group.Q, group.G, group.H, y, t} {
+		b := v.Bytes()
+		h.Write([]byte{byte(len(b) >> 8), byte(len(b))})
+		h.Write(b)
+	}
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, group.Q)
+}
+
+// ProveKnowledge proves knowledge of secret and the blinding value of its
+// commitment, both in [0, prime), against the commitment
+// G^secret * H^blinding that FeldmanVSS publishes as commitments[0] when
+// dealing with PedersenCommitment. The dealer knows both; prime must be
+// large enough for NewPedersenCommitment.
+func ProveKnowledge(secret, blinding *big.Int, prime *big.Int) (*ThresholdProof, error) {
+	group, err := NewPedersenCommitment(prime)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range []*big.Int{secret, blinding} {
+		if v.Sign() < 0 || v.Cmp(prime) >= 0 {
+			return nil, ErrSecretOutOfRange
+		}
+	}
+
+	k, err := rand.Int(rand.Reader, group.Q)
+	if err != nil {
+		return nil, err
+	}
+	l, err := rand.Int(rand.Reader, group.Q)
+	if err != nil {
+		return nil, err
+	}
+	t := group.Commit(k, l)
+	y := group.Commit(secret, blinding)
+
+	c := proofChallenge(group, y, t)
+	s := new(big.Int).Mul(c, secret)
+	s.Add(s, k).Mod(s, group.Q)
+	u := new(big.Int).Mul(c, blinding)
+	u.Add(u, l).Mod(u, group.Q)
+	return &ThresholdProof{Commitment: t, Response: s, BlindingResponse: u}, nil
+}
+
+// VerifyKnowledge checks a proof against commitments[0], the dealer's
+// commitment to the secret, by testing G^s * H^u == T * C^c mod P. It only
+// applies to commitments made with the Pedersen group of order prime, as
+// FeldmanVSS makes with PedersenCommitment.
+func VerifyKnowledge(proof *ThresholdProof, commitments []*big.Int, prime *big.Int) bool {
+	if proof == nil || proof.Commitment == nil || proof.Response == nil || proof.BlindingResponse == nil ||
+		len(commitments) == 0 || commitments[0] == nil {
+		return false
+	}
+	group, err := NewPedersenCommitment(prime)
+	if err != nil {
+		return false
+	}
+	y, t, s, u := commitments[0], proof.Commitment, proof.Response, proof.BlindingResponse
+
+	if !inSubgroup(group, y) || !inSubgroup(group, t) {
+		return false
+	}
+	for _, v := range []*big.Int{s, u} {
+		if v.Sign() < 0 || v.Cmp(group.Q) >= 0 {
+			return false
+		}
+	}
+
+	c := proofChallenge(group, y, t)
+	lhs := group.Commit(s, u)
+	rhs := new(big.Int).Exp(y, c, group.P)
+	rhs.Mul(rhs, t).Mod(rhs, group.P)
+	return lhs.Cmp(rhs) == 0
+}
+
+// inSubgroup reports whether v is in the order-Q subgroup of Z_P*
+func inSubgroup(group *PedersenCommitment, v *big.Int) bool {
+	return v.Sign() > 0 && v.Cmp(group.P) < 0 &&
+		new(big.Int).Exp(v, group.Q, group.P).Cmp(big.NewInt(1)) == 0
+}
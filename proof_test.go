@@ -0,0 +1,66 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestThresholdProof(t *testing.T) {
+	group, err := NewPedersenCommitment(Prime521)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, blinding := big.NewInt(1234567), big.NewInt(89101112)
+	commitments := []*big.Int{group.Commit(secret, blinding)}
+
+	proof, err := ProveKnowledge(secret, blinding, Prime521)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !VerifyKnowledge(proof, commitments, Prime521) {
+		t.Fatal("valid proof rejected")
+	}
+
+	// A prover who does not know the opening proves the wrong statement
+	for _, witness := range []struct{ secret, blinding *big.Int }{
+		{big.NewInt(1234568), blinding},
+		{secret, big.NewInt(89101113)},
+	} {
+		wrong, err := ProveKnowledge(witness.secret, witness.blinding, Prime521)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if VerifyKnowledge(wrong, commitments, Prime521) {
+			t.Fatalf("proof for (%s, %s) verified against the commitment to (%s, %s)",
+				witness.secret, witness.blinding, secret, blinding)
+		}
+	}
+
+	tampered := *proof
+	tampered.Response = new(big.Int).Add(proof.Response, big.NewInt(1))
+	if VerifyKnowledge(&tampered, commitments, Prime521) {
+		t.Fatal("proof with a tampered response verified")
+	}
+	other := []*big.Int{group.Commit(secret, big.NewInt(5))}
+	for name, tc := range map[string]struct {
+		proof       *ThresholdProof
+		commitments []*big.Int
+	}{
+		"other commitment": {proof, other},
+		"nil proof":        {nil, commitments},
+		"empty proof":      {&ThresholdProof{}, commitments},
+		"no commitments":   {proof, nil},
+		"identity":         {proof, []*big.Int{big.NewInt(1)}},
+	} {
+		if VerifyKnowledge(tc.proof, tc.commitments, Prime521) {
+			t.Errorf("%s: verified", name)
+		}
+	}
+
+	if _, err := ProveKnowledge(Prime521, blinding, Prime521); err == nil {
+		t.Error("secret equal to the prime was accepted")
+	}
+	if _, err := ProveKnowledge(secret, blinding, Prime31); err == nil {
+		t.Error("proved over a field too small for commitments")
+	}
+}
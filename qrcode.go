@@ -0,0 +1,476 @@
+package main
+
+import (
+	"errors"
+)
+
+// A small QR Code encoder (ISO/IEC 18004) supporting byte mode at error
+// correction level M for versions 1 to 10. That covers payloads of up to
+// 213 bytes, which is plenty for chunked share strings.
+
+// ErrQRDataTooLong is returned when data does not fit in a version 10 symbol
+var ErrQRDataTooLong = errors.New("data too long for a QR code")
+
+const qrMaxVersion = 10
+
+// Error correction codewords per block for level M, indexed by version
+var qrECCPerBlock = [qrMaxVersion + 1]int{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26}
+
+// Number of error correction blocks for level M, indexed by version
+var qrNumBlocks = [qrMaxVersion + 1]int{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5}
+
+// Format bits identifying level M
+const qrLevelMBits = 0
+
+// qrSymbol is a QR code being built; modules[y][x] is true for dark modules
+type qrSymbol struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// encodeQR encodes data in byte mode and returns the module grid
+func encodeQR(data []byte) ([][]bool, error) {
+	version := 0
+	for v := 1; v <= qrMaxVersion; v++ {
+		if qrDataBits(data, v) <= qrNumDataCodewords(v)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrQRDataTooLong
+	}
+
+	codewords := qrAddECCAndInterleave(qrDataCodewords(data, version), version)
+
+	q := newQRSymbol(version)
+	q.drawFunctionPatterns(version)
+	q.drawCodewords(codewords)
+
+	// Pick the mask with the lowest penalty score
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		penalty := q.penaltyScore()
+		if bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask) // XOR again to undo
+	}
+	q.applyMask(bestMask)
+	q.drawFormatBits(bestMask)
+
+	return q.modules, nil
+}
+
+// qrDataBits is the length of the byte-mode bit stream before padding
+func qrDataBits(data []byte, version int) int {
+	return 4 + qrCountBits(version) + 8*len(data)
+}
+
+// qrCountBits is the width of the byte-mode character count field
+func qrCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// qrNumRawDataModules counts the modules available for codewords
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// qrNumDataCodewords is the number of data (non-ECC) codewords
+func qrNumDataCodewords(version int) int {
+	return qrNumRawDataModules(version)/8 - qrECCPerBlock[version]*qrNumBlocks[version]
+}
+
+// qrDataCodewords builds the padded byte-mode data codewords
+func qrDataCodewords(data []byte, version int) []byte {
+	capacity := qrNumDataCodewords(version) * 8
+
+	var bits []bool
+	appendBits := func(value, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, (value>>i)&1 != 0)
+		}
+	}
+
+	appendBits(0x4, 4) // byte mode
+	appendBits(len(data), qrCountBits(version))
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	// Terminator, then pad to a byte boundary
+	appendBits(0, min(4, capacity-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+
+	// Alternate pad bytes until the capacity is filled
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		appendBits(pad, 8)
+	}
+
+	result := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			result[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+	return result
+}
+
+// qrAddECCAndInterleave splits data into blocks, appends Reed–Solomon ECC to
+// each and interleaves the result
+func qrAddECCAndInterleave(data []byte, version int) []byte {
+	numBlocks := qrNumBlocks[version]
+	blockECCLen := qrECCPerBlock[version]
+	rawCodewords := qrNumRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := qrReedSolomonDivisor(blockECCLen)
+	blocks := make([][]byte, numBlocks)
+
+	k := 0
+	for i := 0; i < numBlocks; i++ {
+		dataLen := shortBlockLen - blockECCLen
+		if i >= numShortBlocks {
+			dataLen++
+		}
+		dat := data[k : k+dataLen]
+		k += dataLen
+
+		block := append([]byte{}, dat...)
+		if i < numShortBlocks {
+			// Placeholder so every block has the same length; skipped below
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, qrReedSolomonRemainder(dat, divisor)...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECCLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// qrReedSolomonDivisor returns the generator polynomial of the given degree
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = qrGFMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrGFMultiply(root, 0x02)
+	}
+	return result
+}
+
+// qrReedSolomonRemainder computes the ECC codewords for a block of data
+func qrReedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= qrGFMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// qrGFMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1
+func qrGFMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+func newQRSymbol(version int) *qrSymbol {
+	size := version*4 + 17
+	q := &qrSymbol{
+		size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for i := 0; i < size; i++ {
+		q.modules[i] = make([]bool, size)
+		q.isFunction[i] = make([]bool, size)
+	}
+	return q
+}
+
+// setFunction sets a function module at column x, row y
+func (q *qrSymbol) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+func (q *qrSymbol) drawFunctionPatterns(version int) {
+	// Timing patterns
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns and separators in three corners
+	q.drawFinder(3, 3)
+	q.drawFinder(q.size-4, 3)
+	q.drawFinder(3, q.size-4)
+
+	// Alignment patterns, skipping the finder corners
+	positions := qrAlignmentPositions[version]
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			q.drawAlignment(x, y)
+		}
+	}
+
+	// Reserve the format areas; real bits are drawn after masking
+	q.drawFormatBits(0)
+	q.drawVersion(version)
+}
+
+func (q *qrSymbol) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			dist := max(abs(dx), abs(dy))
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < q.size && yy >= 0 && yy < q.size {
+				q.setFunction(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (q *qrSymbol) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// Alignment pattern centre coordinates, indexed by version
+var qrAlignmentPositions = [qrMaxVersion + 1][]int{
+	nil, nil,
+	{6, 18}, {6, 22}, {6, 26}, {6, 30}, {6, 34},
+	{6, 22, 38}, {6, 24, 42}, {6, 26, 46}, {6, 28, 50},
+}
+
+func (q *qrSymbol) drawFormatBits(mask int) {
+	data := qrLevelMBits<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	bit := func(i int) bool { return (bits>>uint(i))&1 != 0 }
+
+	// First copy, around the top-left finder
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+
+	// Second copy, split between the other two finders
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(i))
+	}
+	q.setFunction(8, q.size-8, true) // always dark
+}
+
+func (q *qrSymbol) drawVersion(version int) {
+	if version < 7 {
+		return
+	}
+
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 != 0
+		a := q.size - 11 + i%3
+		b := i / 3
+		q.setFunction(a, b, dark)
+		q.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places the data in the zigzag column pairs
+func (q *qrSymbol) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = q.size - 1 - vert
+				}
+				if !q.isFunction[y][x] && i < len(data)*8 {
+					q.modules[y][x] = (data[i>>3]>>(7-uint(i&7)))&1 != 0
+					i++
+				}
+				// Remaining modules stay light, which matches the remainder bits
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with a mask pattern
+func (q *qrSymbol) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.isFunction[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penaltyScore rates a masked symbol; lower scores are easier to scan
+func (q *qrSymbol) penaltyScore() int {
+	penalty := 0
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return q.modules[x][y]
+		}
+		return q.modules[y][x]
+	}
+
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < q.size; y++ {
+			// Runs of five or more modules of the same colour
+			run := 1
+			for x := 1; x < q.size; x++ {
+				if at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				penalty += run - 2
+			}
+
+			// Finder-like 1:1:3:1:1 patterns with four light modules on a side
+			for x := 0; x+10 < q.size; x++ {
+				var window [11]bool
+				for k := range window {
+					window[k] = at(x+k, y, transpose)
+				}
+				if window == qrFinderLeft || window == qrFinderRight {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	// 2x2 blocks of the same colour
+	dark := 0
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				c := q.modules[y][x]
+				if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	// Balance of dark and light modules
+	total := q.size * q.size
+	deviation := abs(dark*20 - total*10)
+	penalty += (deviation + total - 1) / total * 10
+
+	return penalty
+}
+
+var (
+	qrFinderLeft  = [11]bool{true, false, true, true, true, false, true, false, false, false, false}
+	qrFinderRight = [11]bool{false, false, false, false, true, false, true, true, true, false, true}
+)
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Rekey migrates shares from oldPrime to newPrime by reconstructing each
+// secret under the old field and sharing it afresh under the new one. The
+// input is validated against oldPrime, whatever this instance's own prime.
+// Every secret must be below newPrime. Reconstruct the result on an instance
+// given newPrime with SetPrime.
+func (sss *ShamirSecretSharing) Rekey(allShares [][]Point, oldPrime, newPrime *big.Int) ([][]Point, error) {
+	for _, prime := range []*big.Int{oldPrime, newPrime} {
+		if prime == nil || !prime.ProbablyPrime(32) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPrime, prime)
+		}
+	}
+	// As in SetPrime, the largest new x-coordinate must stay below the prime
+	if big.NewInt(int64(sss.xOffset+sss.numShares)).Cmp(newPrime) >= 0 {
+		return nil, fmt.Errorf("%w: %d shares from x offset %d with prime %s", ErrTooManyShares, sss.numShares, sss.xOffset, newPrime)
+	}
+	allShares, err := sss.prepareSharesPrime(allShares, oldPrime)
+	if err != nil {
+		return nil, err
+	}
+
+	rekeyed := make([][]Point, len(allShares))
+	for i, shares := range allShares {
+		var secret *big.Int
+		if sss.usesXOR() {
+			secret = sss.xorCombine(shares)
+		} else {
+			secret = lagrangeAtZero(shares[:sss.threshold], oldPrime)
+		}
+		if secret.Cmp(newPrime) >= 0 {
+			return nil, fmt.Errorf("secret %d does not fit below the new prime", i)
+		}
+
+		newShares, err := sss.generateSharesPrime(secret, newPrime)
+		if err != nil {
+			return nil, err
+		}
+		rekeyed[i] = newShares
+	}
+	return rekeyed, nil
+}
+
+// generateSharesPrime is GenerateShares over an arbitrary prime field
+func (sss *ShamirSecretSharing) generateSharesPrime(secret, prime *big.Int) ([]Point, error) {
+	if sss.usesXOR() {
+		return sss.xorSplit(secret, prime), nil
+	}
+
+	coefficients := make([]*big.Int, sss.threshold)
+	coefficients[0] = secret
+	for i := 1; i < sss.threshold; i++ {
+		coeff, err := rand.Int(rand.Reader, prime)
+		if err != nil {
+			return nil, err
+		}
+		coefficients[i] = coeff
+	}
+
+	shares := make([]Point, sss.numShares)
+	for i := range shares {
+		x := big.NewInt(int64(sss.xOffset + i + 1))
+		shares[i] = Point{X: x, Y: evaluateCoefficients(coefficients, x, prime)}
+	}
+	return shares, nil
+}
+
+// RevokeAndReissue reconstructs the secret from a quorum of shares and
+// shares it again on a fresh random polynomial. Every participant's share
+// changes, including those of holders who were not compromised: issuing a
+// replacement on the old polynomial would leave the compromised share as
+// valid as before, so re-sharing is the only sound revocation. Distribute
+// the whole new set and destroy all of the old shares.
+func (sss *ShamirSecretSharing) RevokeAndReissue(shares []Point) ([]Point, error) {
+	secret, _, err := sss.ReconstructSecretVerbose(shares)
+	if err != nil {
+		return nil, err
+	}
+	return sss.GenerateShares(secret), nil
+}
+
+// MigrateShares moves a secret to a new threshold and share count without
+// ever reconstructing it. Each of oldThreshold old holders shares their own
+// share under the new parameters, and new holder j combines the sub-shares
+// they receive weighted by the old Lagrange coefficients; the weighted sum
+// of the old shares is the secret, so the new shares are shares of it too.
+//
+// Exactly oldThreshold old holders must take part, and only their shares are
+// used. The old shares must be polynomial shares over this instance's prime,
+// not an XOR split, and the new shares are polynomial shares too. Here the
+// whole exchange runs in one process; in a real deployment each old holder
+// runs their part and sends sub-shares privately.
+func (sss *ShamirSecretSharing) MigrateShares(oldShares []Point, oldThreshold, newThreshold, newNumShares int) ([]Point, error) {
+	if oldThreshold < 1 || len(oldShares) < oldThreshold {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrInsufficientShares, len(oldShares), oldThreshold)
+	}
+	if sss.usesXOR() {
+		return nil, fmt.Errorf("%w: XOR shares cannot be migrated", ErrInvalidThreshold)
+	}
+
+	next, err := NewShamirSecretSharing(newThreshold, newNumShares)
+	if err != nil {
+		return nil, err
+	}
+	next.SetXOffset(sss.xOffset)
+	if err := next.SetPrime(sss.prime); err != nil {
+		return nil, err
+	}
+
+	holders := oldShares[:oldThreshold]
+	if err := next.checkSharePoints(holders); err != nil {
+		return nil, err
+	}
+	xs := make([]*big.Int, len(holders))
+	seen := make(map[string]bool, len(holders))
+	for i, p := range holders {
+		if seen[p.X.String()] {
+			return nil, fmt.Errorf("%w: duplicate share for x = %s", ErrInsufficientShares, p.X)
+		}
+		seen[p.X.String()] = true
+		xs[i] = p.X
+	}
+
+	newShares := make([]Point, newNumShares)
+	for j := range newShares {
+		newShares[j] = Point{X: big.NewInt(int64(sss.xOffset + j + 1)), Y: new(big.Int)}
+	}
+
+	zero := big.NewInt(0)
+	for i, holder := range holders {
+		lambda := lagrangeBasisAt(xs, i, zero, sss.prime)
+		for j, sub := range next.GenerateShares(holder.Y) {
+			term := new(big.Int).Mul(lambda, sub.Y)
+			newShares[j].Y.Add(newShares[j].Y, term).Mod(newShares[j].Y, sss.prime)
+		}
+	}
+	return newShares, nil
+}
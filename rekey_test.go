@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func TestRekeyToLargerPrime(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	allShares := mustShareText(t, sss, "rekey me")
+
+	rekeyed, err := sss.Rekey(allShares, Prime31, Prime127)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large := newTestSharing(t, 3, 5)
+	if err := large.SetPrime(Prime127); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := large.ReconstructText(subsetShares(rekeyed, 1, 3, 4)); err != nil || text != "rekey me" {
+		t.Fatalf("new field: got %q, %v; want \"rekey me\"", text, err)
+	}
+	// The new shares use the wider field; all 40 landing below 2^31 by
+	// chance has probability 2^-3840
+	wide := false
+	for _, shares := range rekeyed {
+		for _, p := range shares {
+			wide = wide || p.Y.Cmp(Prime31) >= 0
+		}
+	}
+	if !wide {
+		t.Fatal("every rekeyed share is below the old prime")
+	}
+}
+
+func TestRekeySecretTooLarge(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if err := sss.SetPrime(Prime61); err != nil {
+		t.Fatal(err)
+	}
+	secret := new(big.Int).Add(Prime31, big.NewInt(5))
+	allShares := [][]Point{sss.GenerateShares(secret)}
+	if _, err := sss.Rekey(allShares, Prime61, Prime31); err == nil {
+		t.Fatal("secret above the new prime was migrated")
+	}
+	if _, err := sss.Rekey(allShares, Prime61, big.NewInt(3)); err == nil {
+		t.Fatal("three shares over GF(3) were accepted")
+	}
+}
+
+// subsetShares keeps the given participants' shares of every secret
+func subsetShares(allShares [][]Point, participants ...int) [][]Point {
+	subset := make([][]Point, len(allShares))
+	for i, shares := range allShares {
+		for _, p := range participants {
+			subset[i] = append(subset[i], shares[p])
+		}
+	}
+	return subset
+}
+
+func TestRevokeAndReissue(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	secret := big.NewInt(271828)
+	old := sss.GenerateShares(secret)
+
+	// Participant 1's share leaked; the other two reissue
+	reissued, err := sss.RevokeAndReissue([]Point{old[0], old[2]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sss.ReconstructSecret(reissued[1:]); got.Cmp(secret) != 0 {
+		t.Fatalf("new shares reconstruct to %s, want %s", got, secret)
+	}
+	for i := range old {
+		if reissued[i].X.Cmp(old[i].X) != 0 || reissued[i].Y.Cmp(old[i].Y) == 0 {
+			t.Fatalf("share %d: old %v, new %v; want the same x and a new y", i, old[i], reissued[i])
+		}
+	}
+	// The leaked share no longer combines with any current share
+	for _, current := range []Point{reissued[0], reissued[2]} {
+		if got := sss.ReconstructSecret([]Point{old[1], current}); got.Cmp(secret) == 0 {
+			t.Fatalf("leaked share with x = %s of the new set still recovers the secret", current.X)
+		}
+	}
+
+	if _, err := sss.RevokeAndReissue(old[:1]); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("one share: got %v, want ErrInsufficientShares", err)
+	}
+}
+
+func TestMigrateShares(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	secret := big.NewInt(314159)
+	old := sss.GenerateShares(secret)
+
+	migrated, err := sss.MigrateShares(old[1:], 2, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrated) != 5 {
+		t.Fatalf("got %d shares, want 5", len(migrated))
+	}
+	next := newTestSharing(t, 3, 5)
+	for _, idx := range [][]int{{0, 1, 2}, {0, 2, 4}, {1, 3, 4}, {2, 3, 4}} {
+		subset := []Point{migrated[idx[0]], migrated[idx[1]], migrated[idx[2]]}
+		if got := next.ReconstructSecret(subset); got.Cmp(secret) != 0 {
+			t.Fatalf("shares %v reconstruct to %s, want %s", idx, got, secret)
+		}
+	}
+	// Two shares are now below the threshold; a match has probability 2^-31
+	if got := lagrangeAtZero(migrated[:2], Prime31); got.Cmp(secret) == 0 {
+		t.Fatal("two migrated shares still recover the secret")
+	}
+
+	if _, err := sss.MigrateShares(old[:1], 2, 3, 5); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("one old share: got %v, want ErrInsufficientShares", err)
+	}
+	if _, err := sss.MigrateShares([]Point{old[0], old[0]}, 2, 3, 5); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("duplicate old share: got %v, want ErrInsufficientShares", err)
+	}
+}
+
+func TestRekeyValidatesAgainstOldPrime(t *testing.T) {
+	// The instance keeps the default Prime31 while migrating Prime61 shares
+	sss := newTestSharing(t, 2, 3)
+	old := newTestSharing(t, 2, 3)
+	if err := old.SetPrime(Prime61); err != nil {
+		t.Fatal(err)
+	}
+	secret := big.NewInt(1 << 40)
+	shares := old.GenerateShares(secret)
+	wide := false
+	for _, p := range shares {
+		wide = wide || p.Y.Cmp(Prime31) >= 0
+	}
+	if !wide {
+		t.Skip("every Prime61 share landed below Prime31; probability about 2^-90")
+	}
+
+	rekeyed, err := sss.Rekey([][]Point{shares}, Prime61, Prime127)
+	if err != nil {
+		t.Fatalf("valid Prime61 shares above the instance's prime: %v", err)
+	}
+	large := newTestSharing(t, 2, 3)
+	if err := large.SetPrime(Prime127); err != nil {
+		t.Fatal(err)
+	}
+	if got := large.ReconstructSecret(rekeyed[0][1:]); got.Cmp(secret) != 0 {
+		t.Fatalf("rekeyed shares reconstruct to %s, want %s", got, secret)
+	}
+
+	// A y outside the old field is rejected even though it fits the new one
+	bad := slices.Clone(shares)
+	bad[0] = Point{X: shares[0].X, Y: new(big.Int).Add(Prime61, big.NewInt(1))}
+	if _, err := large.Rekey([][]Point{bad}, Prime61, Prime127); !errors.Is(err, ErrInvalidShare) {
+		t.Fatalf("y above the old prime: got %v, want ErrInvalidShare", err)
+	}
+
+	// New x-coordinates run from xOffset+1, so the offset counts against the prime
+	offset := newTestSharing(t, 2, 3)
+	offset.SetXOffset(5)
+	small := []Point{{X: big.NewInt(1), Y: big.NewInt(2)}, {X: big.NewInt(2), Y: big.NewInt(3)}}
+	if _, err := offset.Rekey([][]Point{small}, Prime31, big.NewInt(7)); !errors.Is(err, ErrTooManyShares) {
+		t.Fatalf("x up to 8 over GF(7): got %v, want ErrTooManyShares", err)
+	}
+	if _, err := sss.Rekey([][]Point{small}, Prime31, big.NewInt(8)); !errors.Is(err, ErrInvalidPrime) {
+		t.Fatalf("composite new modulus: got %v, want ErrInvalidPrime", err)
+	}
+}
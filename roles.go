@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrMissingRole is returned when a required role has no share present
+var ErrMissingRole = errors.New("missing share from required role")
+
+// Role describes a group of share holders
+type Role struct {
+	Name                   string
+	Count                  int  // number of shares issued to this role
+	RequiredForReconstruct bool // a share from this role must always be present
+}
+
+// RoleBasedShare is a share assigned to a named role
+type RoleBasedShare struct {
+	Role  string
+	Share Point
+}
+
+// RequiredRoles lists the names of roles marked RequiredForReconstruct
+func RequiredRoles(roles []Role) []string {
+	var required []string
+	for _, role := range roles {
+		if role.RequiredForReconstruct {
+			required = append(required, role.Name)
+		}
+	}
+	return required
+}
+
+// GenerateRoleShares shares a secret and hands out the shares to roles in
+// order. The role counts must add up to the number of shares.
+func (sss *ShamirSecretSharing) GenerateRoleShares(secret *big.Int, roles []Role) ([]RoleBasedShare, error) {
+	total := 0
+	seen := make(map[string]bool)
+	for _, role := range roles {
+		if role.Count < 1 {
+			return nil, fmt.Errorf("role %q must receive at least one share", role.Name)
+		}
+		if seen[role.Name] {
+			return nil, fmt.Errorf("duplicate role %q", role.Name)
+		}
+		seen[role.Name] = true
+		total += role.Count
+	}
+	if total != sss.numShares {
+		return nil, fmt.Errorf("roles hold %d shares, expected %d", total, sss.numShares)
+	}
+
+	shares := sss.GenerateShares(secret)
+	roleShares := make([]RoleBasedShare, 0, len(shares))
+
+	idx := 0
+	for _, role := range roles {
+		for i := 0; i < role.Count; i++ {
+			roleShares = append(roleShares, RoleBasedShare{Role: role.Name, Share: shares[idx]})
+			idx++
+		}
+	}
+
+	return roleShares, nil
+}
+
+// ReconstructWithRoles reconstructs the secret after checking that every
+// required role is represented. Shares from required roles are always
+// among the points used for interpolation.
+func (sss *ShamirSecretSharing) ReconstructWithRoles(shares []RoleBasedShare, required []string) (*big.Int, error) {
+	present := make(map[string]bool)
+	for _, share := range shares {
+		present[share.Role] = true
+	}
+
+	var missing []string
+	for _, name := range required {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingRole, strings.Join(missing, ", "))
+	}
+
+	// Put required roles first so they are part of the interpolation subset
+	isRequired := make(map[string]bool)
+	for _, name := range required {
+		isRequired[name] = true
+	}
+
+	points := make([]Point, 0, len(shares))
+	for _, share := range shares {
+		if isRequired[share.Role] {
+			points = append(points, share.Share)
+		}
+	}
+	for _, share := range shares {
+		if !isRequired[share.Role] {
+			points = append(points, share.Share)
+		}
+	}
+
+	// A participant submitted twice must not count twice towards the quorum
+	points, err := DedupeShares(points)
+	if err != nil {
+		return nil, err
+	}
+	if needed := sss.sharesNeeded(); len(points) < needed {
+		return nil, fmt.Errorf("%w: have %d distinct, need %d", ErrInsufficientShares, len(points), needed)
+	}
+	if err := sss.checkSharePoints(points); err != nil {
+		return nil, err
+	}
+
+	return sss.ReconstructSecret(points), nil
+}
+
+// ReconstructIfQuorum reconstructs the secret only if the quorum policy is
+// met: every participant named in required has a share present, and there
+// are enough distinct shares for the threshold. Each share's Role is its
+// participant's label, so a participant is simply a role of one share. The
+// error wraps ErrMissingRole, naming the absent participants, or
+// ErrInsufficientShares.
+func (sss *ShamirSecretSharing) ReconstructIfQuorum(shares []RoleBasedShare, required []string) (*big.Int, error) {
+	secret, err := sss.ReconstructWithRoles(shares, required)
+	if err != nil {
+		return nil, fmt.Errorf("quorum not met: %w", err)
+	}
+	return secret, nil
+}
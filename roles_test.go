@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// testRoles issues one required officer share and four clerk shares
+var testRoles = []Role{
+	{Name: "officer", Count: 1, RequiredForReconstruct: true},
+	{Name: "clerk", Count: 4},
+}
+
+func TestReconstructWithRoles(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	secret := big.NewInt(424242)
+	shares, err := sss.GenerateRoleShares(secret, testRoles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	required := RequiredRoles(testRoles)
+
+	// Every optional share on top of the officer's is more than enough
+	got, err := sss.ReconstructWithRoles(shares, required)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("all shares: got %v, %v; want %s", got, err, secret)
+	}
+	// The officer's share is last here but must still be used
+	got, err = sss.ReconstructWithRoles([]RoleBasedShare{shares[3], shares[4], shares[0]}, required)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("officer last: got %v, %v; want %s", got, err, secret)
+	}
+
+	if _, err := sss.ReconstructWithRoles(shares[1:], required); !errors.Is(err, ErrMissingRole) {
+		t.Errorf("no officer share: got %v, want ErrMissingRole", err)
+	}
+}
+
+func TestReconstructWithRolesDuplicateParticipant(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	shares, err := sss.GenerateRoleShares(big.NewInt(424242), testRoles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	twice := []RoleBasedShare{shares[0], shares[1], shares[1]}
+	if _, err := sss.ReconstructWithRoles(twice, RequiredRoles(testRoles)); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("clerk submitted twice: got %v, want ErrInsufficientShares", err)
+	}
+
+	forged := shares[1]
+	forged.Share.Y = new(big.Int).Add(forged.Share.Y, big.NewInt(1))
+	conflicting := []RoleBasedShare{shares[0], shares[1], forged, shares[2]}
+	if _, err := sss.ReconstructWithRoles(conflicting, RequiredRoles(testRoles)); !errors.Is(err, ErrInconsistentShares) {
+		t.Fatalf("conflicting shares for one x: got %v, want ErrInconsistentShares", err)
+	}
+}
+
+func TestReconstructWithRolesNamesMissing(t *testing.T) {
+	// Alice and Bob must both consent; either trustee may make up the quorum
+	roles := []Role{
+		{Name: "alice", Count: 1, RequiredForReconstruct: true},
+		{Name: "bob", Count: 1, RequiredForReconstruct: true},
+		{Name: "trustee", Count: 2},
+	}
+	sss := newTestSharing(t, 3, 4)
+	secret := big.NewInt(5150)
+	shares, err := sss.GenerateRoleShares(secret, roles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	required := RequiredRoles(roles)
+
+	_, err = sss.ReconstructWithRoles(shares[2:], required)
+	if !errors.Is(err, ErrMissingRole) || !strings.HasSuffix(err.Error(), ": alice, bob") {
+		t.Fatalf("both absent: got %v, want ErrMissingRole naming alice and bob", err)
+	}
+	_, err = sss.ReconstructWithRoles([]RoleBasedShare{shares[0], shares[2], shares[3]}, required)
+	if !errors.Is(err, ErrMissingRole) || !strings.HasSuffix(err.Error(), ": bob") {
+		t.Fatalf("bob absent: got %v, want ErrMissingRole naming only bob", err)
+	}
+
+	got, err := sss.ReconstructWithRoles([]RoleBasedShare{shares[3], shares[1], shares[0]}, required)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("both present: got %v, %v; want %s", got, err, secret)
+	}
+}
+
+func TestReconstructIfQuorum(t *testing.T) {
+	participants := []Role{
+		{Name: "ceo", Count: 1},
+		{Name: "cfo", Count: 1},
+		{Name: "auditor", Count: 1},
+		{Name: "engineer", Count: 1},
+	}
+	sss := newTestSharing(t, 2, 4)
+	secret := big.NewInt(8080)
+	shares, err := sss.GenerateRoleShares(secret, participants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	required := []string{"ceo", "auditor"}
+
+	// Two shares meet the threshold, but the auditor has not consented
+	_, err = sss.ReconstructIfQuorum([]RoleBasedShare{shares[0], shares[1]}, required)
+	if !errors.Is(err, ErrMissingRole) || !strings.HasSuffix(err.Error(), ": auditor") {
+		t.Fatalf("auditor absent: got %v, want ErrMissingRole naming the auditor", err)
+	}
+
+	got, err := sss.ReconstructIfQuorum([]RoleBasedShare{shares[3], shares[2], shares[0]}, required)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("ceo and auditor present: got %v, %v; want %s", got, err, secret)
+	}
+
+	// The required participants alone also meet the threshold here; with a
+	// higher threshold they must be joined by others
+	strict := newTestSharing(t, 3, 4)
+	shares, err = strict.GenerateRoleShares(secret, participants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.ReconstructIfQuorum([]RoleBasedShare{shares[0], shares[2]}, required); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("required only, below threshold: got %v, want ErrInsufficientShares", err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"errors"
+)
+
+// ErrEmptySalt is returned when salted sharing is given no salt
+var ErrEmptySalt = errors.New("salt must not be empty")
+
+// HKDF info string separating the salt keystream from other uses of a salt
+const saltKeystreamInfo = "shamir text salt keystream"
+
+// saltKeystream XORs data with a keystream derived from salt. HKDF turns the
+// salt into an AES-256 key whose CTR stream covers text of any length; the
+// zero IV is safe because each salt gives its own key.
+func saltKeystream(data, salt []byte) ([]byte, error) {
+	if len(salt) == 0 {
+		return nil, ErrEmptySalt
+	}
+	key, err := hkdf.Key(sha256.New, salt, nil, saltKeystreamInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(out, data)
+	return out, nil
+}
+
+// ShareTextWithSalt masks each byte of text with a keystream derived from
+// salt before sharing it, so share sets of the same text made under
+// different salts are unrelated and cannot be combined. Use a fresh random
+// salt per session and keep it with the shares' metadata; it is needed to
+// reconstruct.
+func (sss *ShamirSecretSharing) ShareTextWithSalt(text string, salt []byte) ([][]Point, error) {
+	masked, err := saltKeystream([]byte(text), salt)
+	if err != nil {
+		return nil, err
+	}
+	return sss.ShareArbitraryBytes(masked)
+}
+
+// ReconstructTextWithSalt reverses ShareTextWithSalt. A wrong salt is not
+// detected: it unmasks with the wrong keystream and returns garbage of the
+// right length without an error. Record a digest of the text (see
+// MessageDigest) when a wrong salt must be caught.
+func (sss *ShamirSecretSharing) ReconstructTextWithSalt(allShares [][]Point, salt []byte) (string, error) {
+	masked, err := sss.ReconstructArbitraryBytes(allShares)
+	if err != nil {
+		return "", err
+	}
+	text, err := saltKeystream(masked, salt)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestShareTextWithSalt(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	text := "same text, two sessions"
+	saltA, saltB := []byte("session A"), []byte("session B")
+
+	sharesA, err := sss.ShareTextWithSalt(text, saltA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharesB, err := sss.ShareTextWithSalt(text, saltB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The masked secrets differ, so shares from the two sessions describe
+	// different values; 23 matching bytes by chance has probability 2^-184
+	maskedA, _ := sss.ReconstructArbitraryBytes(sharesA)
+	maskedB, _ := sss.ReconstructArbitraryBytes(sharesB)
+	if string(maskedA) == string(maskedB) || string(maskedA) == text {
+		t.Fatal("different salts gave the same masked secret")
+	}
+
+	for _, tc := range []struct {
+		allShares [][]Point
+		salt      []byte
+	}{{sharesA, saltA}, {sharesB, saltB}} {
+		got, err := sss.ReconstructTextWithSalt(tc.allShares, tc.salt)
+		if err != nil || got != text {
+			t.Fatalf("salt %q: got %q, %v; want %q", tc.salt, got, err, text)
+		}
+	}
+
+	// A wrong salt is not an error: it gives garbage of the same length
+	got, err := sss.ReconstructTextWithSalt(sharesA, saltB)
+	if err != nil {
+		t.Fatalf("wrong salt: unexpected error %v", err)
+	}
+	if got == text || len(got) != len(text) {
+		t.Fatalf("wrong salt: got %q, want %d bytes of garbage", got, len(text))
+	}
+
+	// Mixing shares across sessions does not recover the text either
+	mixed := make([][]Point, len(sharesA))
+	for i := range mixed {
+		mixed[i] = []Point{sharesA[i][0], sharesB[i][1]}
+	}
+	if got, _ := sss.ReconstructTextWithSalt(mixed, saltA); got == text {
+		t.Fatal("shares from two sessions combined to the text")
+	}
+
+	if _, err := sss.ShareTextWithSalt(text, nil); !errors.Is(err, ErrEmptySalt) {
+		t.Fatalf("empty salt: got %v, want ErrEmptySalt", err)
+	}
+	if _, err := sss.ReconstructTextWithSalt(sharesA, nil); !errors.Is(err, ErrEmptySalt) {
+		t.Fatalf("empty salt on reconstruction: got %v, want ErrEmptySalt", err)
+	}
+}
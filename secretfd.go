@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// readFD reads a secret from the already open file descriptor fd, such as
+// one set up by the shell with 3< <(pass show my-secret). Unlike typing the
+// secret at the prompt or passing it as an argument, this keeps it out of
+// shell history and the process list. The whole stream is read and one
+// trailing line ending dropped; the descriptor is closed afterwards.
+func readFD(fd int) (string, error) {
+	if fd < 0 {
+		return "", fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	f := os.NewFile(uintptr(fd), "secret")
+	if f == nil {
+		return "", fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return "", fmt.Errorf("reading secret from file descriptor %d: %w", fd, err)
+	}
+	secret := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(secret, "\r"), nil
+}
@@ -0,0 +1,54 @@
+//go:build linux || darwin
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+// pipeFD returns a descriptor reading content through a pipe. readFD closes
+// the descriptor it is given, so it gets a duplicate and the *os.File
+// wrappers here close their own.
+func pipeFD(t *testing.T, content string) int {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := w.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	fd, err := syscall.Dup(int(r.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd
+}
+
+func TestReadFD(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"trailing newline", "my-secret\n", "my-secret"},
+		{"CRLF", "my-secret\r\n", "my-secret"},
+		{"no newline", "my-secret", "my-secret"},
+		{"only one line ending dropped", "two lines\nof secret\n\n", "two lines\nof secret\n"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		got, err := readFD(pipeFD(t, tt.content))
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := readFD(-1); err == nil {
+		t.Error("read from descriptor -1")
+	}
+}
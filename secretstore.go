@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// ErrKeyNotFound is returned for keys that are not in a SecretStore
+var ErrKeyNotFound = errors.New("key not found")
+
+// SecretStore maps string keys to shared secrets, sharing each value byte by
+// byte like ShareArbitraryBytes. It keeps every share in memory, so it is a
+// convenience for applications and tests rather than a way to split trust.
+// It is safe for concurrent use.
+type SecretStore struct {
+	sss *ShamirSecretSharing
+
+	mu      sync.RWMutex
+	entries map[string][][]Point
+}
+
+// secretStoreFile is the gob form of a saved store
+type secretStoreFile struct {
+	Config  []byte            // SerializeConfig output
+	Entries map[string][]byte // CodecV1-encoded shares
+}
+
+// NewSecretStore creates an empty store that shares values with sss
+func NewSecretStore(sss *ShamirSecretSharing) *SecretStore {
+	return &SecretStore{sss: sss, entries: make(map[string][][]Point)}
+}
+
+// Put shares secret and stores it under key, replacing any earlier value
+func (s *SecretStore) Put(key string, secret []byte) error {
+	shares, err := s.sss.ShareArbitraryBytes(secret)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = shares
+	return nil
+}
+
+// GetShares returns the shares stored under key
+func (s *SecretStore) GetShares(key string) ([][]Point, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shares, ok := s.entries[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, key)
+	}
+	return shares, nil
+}
+
+// Reconstruct recovers the secret stored under key
+func (s *SecretStore) Reconstruct(key string) ([]byte, error) {
+	shares, err := s.GetShares(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.sss.ReconstructArbitraryBytes(shares)
+}
+
+// Delete removes key from the store
+func (s *SecretStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// Keys returns the stored keys in sorted order
+func (s *SecretStore) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Save writes the whole store, including the scheme configuration, to one file
+func (s *SecretStore) Save(filename string) error {
+	config, err := s.sss.SerializeConfig()
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	saved := secretStoreFile{Config: config, Entries: make(map[string][]byte, len(s.entries))}
+	for key, shares := range s.entries {
+		if saved.Entries[key], err = (CodecV1{}).Encode(shares); err != nil {
+			s.mu.RUnlock()
+			return err
+		}
+	}
+	s.mu.RUnlock()
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err := gob.NewEncoder(writer).Encode(saved); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// LoadSecretStore reads a store written by Save, rebuilding its scheme
+func LoadSecretStore(filename string) (*SecretStore, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var saved secretStoreFile
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&saved); err != nil {
+		return nil, err
+	}
+
+	sss, err := DeserializeConfig(saved.Config)
+	if err != nil {
+		return nil, err
+	}
+	s := NewSecretStore(sss)
+	for key, data := range saved.Entries {
+		var shares [][]Point
+		if err := (CodecV1{}).Decode(data, &shares); err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		s.entries[key] = shares
+	}
+	return s, nil
+}
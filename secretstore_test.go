@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSecretStore(t *testing.T) {
+	store := NewSecretStore(newTestSharing(t, 2, 3))
+	values := map[string][]byte{
+		"db":    []byte("hunter2"),
+		"api":   {0, 1, 2, 255},
+		"empty": {},
+	}
+	for key, value := range values {
+		if err := store.Put(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Put("db", []byte("correct horse")); err != nil {
+		t.Fatal(err)
+	}
+	values["db"] = []byte("correct horse")
+
+	if keys := store.Keys(); !slices.Equal(keys, []string{"api", "db", "empty"}) {
+		t.Fatalf("Keys = %v", keys)
+	}
+	for key, want := range values {
+		shares, err := store.GetShares(key)
+		if err != nil || len(shares) != len(want) {
+			t.Fatalf("%s: %d share sets, %v; want %d", key, len(shares), err, len(want))
+		}
+		if got, err := store.Reconstruct(key); err != nil || !bytes.Equal(got, want) {
+			t.Fatalf("%s: got %q, %v; want %q", key, got, err, want)
+		}
+	}
+
+	store.Delete("api")
+	if _, err := store.Reconstruct("api"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("deleted key: got %v, want ErrKeyNotFound", err)
+	}
+	if _, err := store.GetShares("never"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("unknown key: got %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestSecretStorePersistence(t *testing.T) {
+	wide := newTestSharing(t, 2, 3)
+	if err := wide.SetPrime(Prime61); err != nil {
+		t.Fatal(err)
+	}
+	xor := newTestSharing(t, 3, 3)
+	if err := xor.SetScheme(SchemeXOR); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, sss := range map[string]*ShamirSecretSharing{"Prime61": wide, "xor": xor} {
+		store := NewSecretStore(sss)
+		for _, key := range []string{"one", "two"} {
+			if err := store.Put(key, []byte("value "+key)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		path := filepath.Join(t.TempDir(), "store.gob")
+		if err := store.Save(path); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		loaded, err := LoadSecretStore(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if loaded.sss.Prime().Cmp(sss.Prime()) != 0 || loaded.sss.Scheme() != sss.Scheme() {
+			t.Fatalf("%s: loaded store uses prime %s, scheme %v", name, loaded.sss.Prime(), loaded.sss.Scheme())
+		}
+		for _, key := range []string{"one", "two"} {
+			if got, err := loaded.Reconstruct(key); err != nil || string(got) != "value "+key {
+				t.Fatalf("%s, %s: got %q, %v", name, key, got, err)
+			}
+		}
+	}
+}
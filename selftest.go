@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math/big"
+)
+
+// selfTestCase is a named check run by runSelfTest
+type selfTestCase struct {
+	name string
+	run  func() error
+}
+
+// runSelfTest exercises sharing and reconstruction on fixed inputs and
+// reports each check to w. It returns an error if any check fails.
+func runSelfTest(w io.Writer) error {
+	cases := []selfTestCase{
+		{"secret from every 3-of-5 subset", selfTestSubsets},
+		{"secret with full quorum (3-of-3)", selfTestFullQuorum},
+		{"text round trip", selfTestText},
+		{"image round trip", selfTestImage},
+	}
+
+	failures := 0
+	for _, c := range cases {
+		if err := c.run(); err != nil {
+			fmt.Fprintf(w, "FAIL %s: %v\n", c.name, err)
+			failures++
+			continue
+		}
+		fmt.Fprintf(w, "ok   %s\n", c.name)
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d self-test checks failed", failures, len(cases))
+	}
+	return nil
+}
+
+func selfTestSubsets() error {
+	sss, err := NewShamirSecretSharing(3, 5)
+	if err != nil {
+		return err
+	}
+	secret := big.NewInt(123456789)
+	shares := sss.GenerateShares(secret)
+
+	for i := 0; i < len(shares); i++ {
+		for j := i + 1; j < len(shares); j++ {
+			for k := j + 1; k < len(shares); k++ {
+				subset := []Point{shares[i], shares[j], shares[k]}
+				if got := sss.ReconstructSecret(subset); got.Cmp(secret) != 0 {
+					return fmt.Errorf("shares %d,%d,%d gave %s", i+1, j+1, k+1, got)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func selfTestFullQuorum() error {
+	sss, err := NewShamirSecretSharing(3, 3)
+	if err != nil {
+		return err
+	}
+	secret := big.NewInt(987654321)
+
+	if got := sss.ReconstructSecret(sss.GenerateShares(secret)); got.Cmp(secret) != 0 {
+		return fmt.Errorf("got %s", got)
+	}
+	if err := sss.SetScheme(SchemeXOR); err != nil {
+		return err
+	}
+	if got := sss.ReconstructSecret(sss.GenerateShares(secret)); got.Cmp(secret) != 0 {
+		return fmt.Errorf("XOR split: got %s", got)
+	}
+	return nil
+}
+
+func selfTestText() error {
+	const text = "Shamir's Secret Sharing – ünïcödé ✓"
+
+	sss, err := NewShamirSecretSharing(2, 4)
+	if err != nil {
+		return err
+	}
+	allShares, err := sss.ShareText(text)
+	if err != nil {
+		return err
+	}
+
+	got, err := sss.ReconstructText(allShares)
+	if err != nil {
+		return err
+	}
+	if got != text {
+		return fmt.Errorf("got %q", got)
+	}
+	return nil
+}
+
+func selfTestImage() error {
+	const width, height = 16, 8
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x*16 + y)})
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		return err
+	}
+
+	sss, err := NewShamirSecretSharing(3, 5)
+	if err != nil {
+		return err
+	}
+	allShares, w, h, err := sss.ShareImageReader(&encoded)
+	if err != nil {
+		return err
+	}
+
+	pixels, err := sss.ReconstructImageBytes(allShares, w, h)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pixels, img.Pix) {
+		return errors.New("reconstructed pixels differ from the fixture")
+	}
+	return nil
+}
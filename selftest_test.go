@@ -0,0 +1,16 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	var out strings.Builder
+	if err := runSelfTest(&out); err != nil {
+		t.Fatalf("self-test failed: %v\n%s", err, out.String())
+	}
+	if out.Len() == 0 {
+		t.Error("self-test reported nothing")
+	}
+}
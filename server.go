@@ -0,0 +1,325 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//go:embed web
+var webAssets embed.FS
+
+// Largest request body accepted by the JSON endpoints
+const maxRequestBytes = 1 << 20
+
+// Limits on a /share-batch request: the number of secrets and the size of each
+const (
+	maxBatchSecrets     = 100
+	maxBatchSecretBytes = 4 << 10
+)
+
+// Limits on sharing parameters: the share count of any request, and the
+// number of share values one request may produce (bytes times shares)
+const (
+	maxServerShares      = 255
+	maxServerShareValues = 1 << 20
+)
+
+// jsonPoint is a share in API requests and responses; values are decimal
+type jsonPoint struct {
+	X string `json:"x"`
+	Y string `json:"y"`
+}
+
+type shareRequest struct {
+	Text      string `json:"text"`
+	Threshold int    `json:"threshold"`
+	NumShares int    `json:"num_shares"`
+}
+
+type shareResponse struct {
+	Shares [][]jsonPoint `json:"shares"` // indexed by byte, then participant
+}
+
+type shareBatchRequest struct {
+	Secrets   []string `json:"secrets"`
+	Threshold int      `json:"threshold"`
+	NumShares int      `json:"num_shares"`
+}
+
+type shareBatchResponse struct {
+	ShareSets [][][]jsonPoint `json:"share_sets"` // one set per secret, in request order
+}
+
+type reconstructRequest struct {
+	Threshold int           `json:"threshold"`
+	NumShares int           `json:"num_shares"`
+	Shares    [][]jsonPoint `json:"shares"`
+}
+
+type reconstructResponse struct {
+	Text string `json:"text"`
+}
+
+// NewServerHandler serves the web UI and the share and reconstruct endpoints
+func NewServerHandler() http.Handler {
+	assets, err := fs.Sub(webAssets, "web")
+	if err != nil {
+		panic(err) // the embedded directory always exists
+	}
+
+	// Plain patterns keep routing independent of the GODEBUG mux setting,
+	// which defaults to the pre-1.22 behaviour when built without a go.mod
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServerFS(assets))
+	mux.HandleFunc("/share", postOnly(handleShare))
+	mux.HandleFunc("/share-batch", postOnly(handleShareBatch))
+	mux.HandleFunc("/reconstruct", postOnly(handleReconstruct))
+	mux.HandleFunc("/reconstruct/raw", postOnly(handleReconstructRaw))
+	return mux
+}
+
+// postOnly rejects requests that are not POSTs
+func postOnly(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSONError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		h(w, r)
+	}
+}
+
+// Timeouts for server connections. Request bodies are at most
+// maxRequestBytes, so a client slower than this is stalling, and without
+// them a slow client could hold a connection open indefinitely.
+const (
+	serverReadHeaderTimeout = 10 * time.Second
+	serverReadTimeout       = 30 * time.Second
+	serverIdleTimeout       = 2 * time.Minute
+)
+
+// newServer returns the HTTP server for addr, serving NewServerHandler with
+// read timeouts
+func newServer(addr string) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           NewServerHandler(),
+		ReadHeaderTimeout: serverReadHeaderTimeout,
+		ReadTimeout:       serverReadTimeout,
+		IdleTimeout:       serverIdleTimeout,
+	}
+}
+
+// RunServer listens on addr and serves NewServerHandler
+func RunServer(addr string) error {
+	return newServer(addr).ListenAndServe()
+}
+
+func handleShare(w http.ResponseWriter, r *http.Request) {
+	var req shareRequest
+	if err := decodeJSONRequest(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sss, err := newServerSharing(req.Threshold, req.NumShares)
+	if err == nil {
+		err = checkShareOutput(len(req.Text), req.NumShares)
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	allShares, err := sss.ShareText(req.Text)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, shareResponse{Shares: formatJSONShares(allShares)})
+}
+
+// handleShareBatch shares several texts under the same parameters. The whole
+// batch is validated before any sharing, so a bad entry fails the request.
+func handleShareBatch(w http.ResponseWriter, r *http.Request) {
+	var req shareBatchRequest
+	if err := decodeJSONRequest(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.Secrets) == 0 {
+		writeJSONError(w, http.StatusBadRequest, errors.New("secrets must not be empty"))
+		return
+	}
+	if len(req.Secrets) > maxBatchSecrets {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("at most %d secrets per batch, got %d", maxBatchSecrets, len(req.Secrets)))
+		return
+	}
+	total := 0
+	for i, secret := range req.Secrets {
+		if len(secret) > maxBatchSecretBytes {
+			writeJSONError(w, http.StatusBadRequest, fmt.Errorf("secret %d is %d bytes, limit is %d", i, len(secret), maxBatchSecretBytes))
+			return
+		}
+		total += len(secret)
+	}
+
+	sss, err := newServerSharing(req.Threshold, req.NumShares)
+	if err == nil {
+		err = checkShareOutput(total, req.NumShares)
+	}
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp := shareBatchResponse{ShareSets: make([][][]jsonPoint, len(req.Secrets))}
+	for i, secret := range req.Secrets {
+		allShares, err := sss.ShareText(secret)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, err)
+			return
+		}
+		resp.ShareSets[i] = formatJSONShares(allShares)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// newServerSharing builds an instance for a request, refusing share counts
+// above maxServerShares before anything is allocated for them
+func newServerSharing(threshold, numShares int) (*ShamirSecretSharing, error) {
+	if threshold > maxServerShares || numShares > maxServerShares {
+		return nil, fmt.Errorf("%w: threshold %d of %d, the server allows at most %d shares",
+			ErrTooManyShares, threshold, numShares, maxServerShares)
+	}
+	return NewShamirSecretSharing(threshold, numShares)
+}
+
+// checkShareOutput refuses requests that would produce more than
+// maxServerShareValues share values from n bytes
+func checkShareOutput(n, numShares int) error {
+	if n*numShares > maxServerShareValues {
+		return fmt.Errorf("%d bytes with %d shares each is more than %d share values per request",
+			n, numShares, maxServerShareValues)
+	}
+	return nil
+}
+
+// formatJSONShares converts shares to their API form, keeping the indexing
+func formatJSONShares(allShares [][]Point) [][]jsonPoint {
+	out := make([][]jsonPoint, len(allShares))
+	for i, shares := range allShares {
+		out[i] = make([]jsonPoint, len(shares))
+		for j, p := range shares {
+			out[i][j] = jsonPoint{X: p.X.String(), Y: p.Y.String()}
+		}
+	}
+	return out
+}
+
+func handleReconstruct(w http.ResponseWriter, r *http.Request) {
+	var req reconstructRequest
+	if err := decodeJSONRequest(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sss, err := newServerSharing(req.Threshold, req.NumShares)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	allShares, err := parseJSONShares(req.Shares)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	text, err := sss.ReconstructText(allShares)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reconstructResponse{Text: text})
+}
+
+// handleReconstructRaw takes the same request as /reconstruct but streams the
+// reconstructed bytes as application/octet-stream rather than buffering them
+func handleReconstructRaw(w http.ResponseWriter, r *http.Request) {
+	var req reconstructRequest
+	if err := decodeJSONRequest(w, r, &req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sss, err := newServerSharing(req.Threshold, req.NumShares)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	allShares, err := parseJSONShares(req.Shares)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	// Check up front, since errors can no longer be reported once streaming starts
+	if _, err := sss.prepareShares(allShares); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(allShares)))
+	w.WriteHeader(http.StatusOK)
+	sss.ReconstructBytesTo(w, allShares)
+}
+
+// parseJSONShares converts API shares to points
+func parseJSONShares(in [][]jsonPoint) ([][]Point, error) {
+	allShares := make([][]Point, len(in))
+	for i, shares := range in {
+		allShares[i] = make([]Point, len(shares))
+		for j, jp := range shares {
+			x, okX := new(big.Int).SetString(jp.X, 10)
+			y, okY := new(big.Int).SetString(jp.Y, 10)
+			if !okX || !okY || x.Sign() <= 0 {
+				return nil, fmt.Errorf("invalid share %d of secret %d", j, i)
+			}
+			allShares[i][j] = Point{X: x, Y: y}
+		}
+	}
+	return allShares, nil
+}
+
+// decodeJSONRequest reads a size-limited JSON body into v
+func decodeJSONRequest(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	if dec.More() {
+		return errors.New("invalid request: trailing data")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postJSON sends body to path on the server handler and returns the recorder
+func postJSON(t *testing.T, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	NewServerHandler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServerServesIndex(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewServerHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET / status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "<title>Shamir's Secret Sharing</title>") {
+		t.Error("GET / did not serve the index page")
+	}
+}
+
+func TestServerLimitsShareCount(t *testing.T) {
+	for _, tc := range []struct{ path, body string }{
+		{"/share", `{"text":"a","threshold":1,"num_shares":2000000000}`},
+		{"/share", `{"text":"a","threshold":256,"num_shares":256}`},
+		{"/share-batch", `{"secrets":["a"],"threshold":1,"num_shares":2000000000}`},
+		{"/reconstruct", `{"threshold":2,"num_shares":2000000000,"shares":[]}`},
+		{"/share", `{"text":"` + strings.Repeat("a", 5000) + `","threshold":2,"num_shares":255}`},
+	} {
+		rec := postJSON(t, tc.path, tc.body)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s %.60s: status %d, want 400", tc.path, tc.body, rec.Code)
+		}
+	}
+
+	rec := postJSON(t, "/share", `{"text":"ab","threshold":3,"num_shares":255}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("255 shares: status %d: %s", rec.Code, rec.Body)
+	}
+	var resp shareResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Shares) != 2 || len(resp.Shares[0]) != 255 {
+		t.Fatalf("got %d secrets of %d shares, want 2 of 255", len(resp.Shares), len(resp.Shares[0]))
+	}
+}
+
+func TestServerStreamsRawReconstruction(t *testing.T) {
+	payload := make([]byte, 4000)
+	for i := range payload {
+		payload[i] = byte(i * 37)
+	}
+	sss := newTestSharing(t, 2, 3)
+	allShares, err := sss.ShareArbitraryBytes(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(reconstructRequest{Threshold: 2, NumShares: 3, Shares: formatJSONShares(subsetShares(allShares, 0, 2))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewServerHandler())
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/reconstruct/raw", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.ContentLength != int64(len(payload)) {
+		t.Fatalf("status %d, Content-Length %d; want 200 and %d", resp.StatusCode, resp.ContentLength, len(payload))
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/octet-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("streamed body differs from the payload at byte %d", FirstDifference(got, payload))
+	}
+
+	// Errors are still reported as JSON before streaming starts
+	rec := postJSON(t, "/reconstruct/raw", `{"threshold":2,"num_shares":3,"shares":[[{"x":"1","y":"5"}]]}`)
+	if rec.Code != http.StatusBadRequest || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("one share: status %d, Content-Type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestServerShareBatch(t *testing.T) {
+	secrets := []string{"alpha", "b", "gamma ray"}
+	rec := postJSON(t, "/share-batch", `{"secrets":["alpha","b","gamma ray"],"threshold":2,"num_shares":3}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var resp shareBatchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.ShareSets) != len(secrets) {
+		t.Fatalf("got %d share sets, want %d", len(resp.ShareSets), len(secrets))
+	}
+	sss := newTestSharing(t, 2, 3)
+	for i, set := range resp.ShareSets {
+		allShares, err := parseJSONShares(set)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text, err := sss.ReconstructText(subsetShares(allShares, 2, 0)); err != nil || text != secrets[i] {
+			t.Errorf("set %d: got %q, %v; want %q", i, text, err, secrets[i])
+		}
+	}
+
+	for _, body := range []string{
+		`{"secrets":[],"threshold":2,"num_shares":3}`,
+		`{"threshold":2,"num_shares":3}`,
+		`{"secrets":["ok","` + strings.Repeat("x", maxBatchSecretBytes+1) + `"],"threshold":2,"num_shares":3}`,
+		`{"secrets":["a"],"threshold":4,"num_shares":3}`,
+	} {
+		rec := postJSON(t, "/share-batch", body)
+		var errResp map[string]string
+		if rec.Code != http.StatusBadRequest || json.Unmarshal(rec.Body.Bytes(), &errResp) != nil || errResp["error"] == "" {
+			t.Errorf("%.60s: status %d, body %s; want 400 with an error", body, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestServerTimeouts(t *testing.T) {
+	srv := newServer("127.0.0.1:0")
+	if srv.ReadHeaderTimeout <= 0 || srv.ReadTimeout <= 0 || srv.IdleTimeout <= 0 {
+		t.Fatalf("timeouts %v, %v, %v; want all set", srv.ReadHeaderTimeout, srv.ReadTimeout, srv.IdleTimeout)
+	}
+
+	// A client that never finishes its headers is cut off
+	srv.ReadHeaderTimeout = 50 * time.Millisecond
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, "POST /share HTTP/1.1\r\nHost: x\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.ReadAll(conn); err != nil {
+		t.Fatalf("connection not closed by the server: %v", err)
+	}
+}
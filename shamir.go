@@ -2,18 +2,67 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"flag"
 	"fmt"
 	"image"
 	"image/color"
 	"image/png"
+	"io"
 	"math/big"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 )
 
-// Prime used for finite field operations (large prime for security)
+// ErrTruncatedShares is returned when a share file ends before all declared shares
+var ErrTruncatedShares = errors.New("share file is truncated")
+
+// ErrInvalidThreshold is returned when the threshold is not between 1 and the number of shares
+var ErrInvalidThreshold = errors.New("threshold must be between 1 and the number of shares")
+
+// ErrTooManyShares is returned when there are more shares than distinct non-zero x-coordinates
+var ErrTooManyShares = errors.New("number of shares must be less than the prime")
+
+// ErrInsufficientShares is returned when too few distinct shares are supplied
+var ErrInsufficientShares = errors.New("insufficient shares to reconstruct secret")
+
+// ErrInvalidShareIndex is returned when a share's x-coordinate is outside [1, prime)
+var ErrInvalidShareIndex = errors.New("share x-coordinate out of range")
+
+// ErrInvalidShare is returned for shares with missing or out-of-range values
+var ErrInvalidShare = errors.New("invalid share")
+
+// ErrInvalidPrime is returned by SetPrime for values that are not prime
+var ErrInvalidPrime = errors.New("field modulus must be prime")
+
+// ErrInconsistentShares is returned when two shares have the same
+// x-coordinate but different y values
+var ErrInconsistentShares = errors.New("conflicting shares for the same x-coordinate")
+
+// ErrDimensionMismatch is returned when the pixel shares do not exactly fill
+// the image dimensions
+var ErrDimensionMismatch = errors.New("pixel count does not match image dimensions")
+
+// ErrImageTooLarge is returned for images with more pixels than MaxImagePixels
+var ErrImageTooLarge = errors.New("image is too large")
+
+// MaxImagePixels caps the size of images accepted for sharing, guarding
+// against decompression bombs. Zero or less disables the check.
+var MaxImagePixels int64 = 50_000_000
+
+// Prime used for finite field operations (large prime for security). New
+// instances start with this value.
+//
+// Deprecated: changing PRIME affects every instance in the process; give an
+// instance its own field with SetPrime instead.
 var PRIME = big.NewInt(2147483647) // 2^31 - 1
 
 // Point represents a point on the polynomial
@@ -21,20 +70,272 @@ type Point struct {
 	X, Y *big.Int
 }
 
-// ShamirSecretSharing implements the algorithm
+// IsValid reports whether both coordinates are set, x is positive and y is
+// reduced mod prime
+func (p Point) IsValid(prime *big.Int) bool {
+	return p.X != nil && p.Y != nil &&
+		p.X.Sign() > 0 && p.Y.Sign() >= 0 && p.Y.Cmp(prime) < 0
+}
+
+// String formats the point as Point(x=<x>, y=<y>) in decimal
+func (p Point) String() string {
+	return fmt.Sprintf("Point(x=%s, y=%s)", formatCoordinate(p.X, 10), formatCoordinate(p.Y, 10))
+}
+
+// GoString formats the point as a Go expression. It is only valid Go when
+// both coordinates fit in an int64.
+func (p Point) GoString() string {
+	return fmt.Sprintf("main.Point{X: big.NewInt(%s), Y: big.NewInt(%s)}", formatCoordinate(p.X, 10), formatCoordinate(p.Y, 10))
+}
+
+// Format implements fmt.Formatter so the coordinates are printed rather than
+// their pointers: %v and %s use String, %#v uses GoString, %q quotes String
+// and %x prints the coordinates in hex
+func (p Point) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		io.WriteString(f, p.GoString())
+	case verb == 'v' || verb == 's':
+		io.WriteString(f, p.String())
+	case verb == 'q':
+		io.WriteString(f, strconv.Quote(p.String()))
+	case verb == 'x':
+		fmt.Fprintf(f, "Point(x=%s, y=%s)", formatCoordinate(p.X, 16), formatCoordinate(p.Y, 16))
+	default:
+		fmt.Fprintf(f, "%%!%c(%s)", verb, p.String())
+	}
+}
+
+// formatCoordinate writes a coordinate in the given base, tolerating nil
+func formatCoordinate(n *big.Int, base int) string {
+	if n == nil {
+		return "<nil>"
+	}
+	return n.Text(base)
+}
+
+// InterpolationMethod selects the algorithm used to reconstruct secrets
+type InterpolationMethod int
+
+const (
+	// InterpolationLagrange uses Lagrange basis polynomials (the default)
+	InterpolationLagrange InterpolationMethod = iota
+	// InterpolationNewton uses Newton's divided differences
+	InterpolationNewton
+)
+
+// Scheme selects how secrets are split into shares
+type Scheme int
+
+const (
+	// SchemePolynomial makes shares points on a random polynomial (the default)
+	SchemePolynomial Scheme = iota
+	// SchemeXOR splits each secret into random masks that XOR back to it.
+	// It needs every share, so it is only allowed when threshold == numShares,
+	// and its shares are not points on a polynomial.
+	SchemeXOR
+)
+
+// String returns the name used for the scheme in share file headers
+func (s Scheme) String() string {
+	switch s {
+	case SchemePolynomial:
+		return "polynomial"
+	case SchemeXOR:
+		return "xor"
+	}
+	return fmt.Sprintf("Scheme(%d)", int(s))
+}
+
+// ParseScheme returns the Scheme named by String
+func ParseScheme(name string) (Scheme, error) {
+	switch name {
+	case "polynomial":
+		return SchemePolynomial, nil
+	case "xor":
+		return SchemeXOR, nil
+	}
+	return 0, fmt.Errorf("unknown sharing scheme %q", name)
+}
+
+// ConversionFunc maps an image pixel to the 8-bit value that is shared for
+// it. GrayModelConversion is the default.
+type ConversionFunc func(color.Color) uint8
+
+// GrayModelConversion converts with color.GrayModel, weighting red, green and
+// blue by their standard luminance coefficients
+func GrayModelConversion(c color.Color) uint8 {
+	return color.GrayModel.Convert(c).(color.Gray).Y
+}
+
+// ShamirSecretSharing implements the algorithm.
+//
+// A single instance may be shared by many goroutines: sharing and
+// reconstruction only read the configuration, and scratch values come from a
+// sync.Pool. The Set* methods are not synchronised and should be called
+// before the instance is used concurrently.
 type ShamirSecretSharing struct {
-	threshold int
-	numShares int
+	threshold     int
+	numShares     int
+	progress      func(done, total int)
+	interpolation InterpolationMethod
+	xOffset       int
+	prime         *big.Int
+	backend       Backend
+	concurrency   int
+	conversion    ConversionFunc
+	scheme        Scheme
+
+	// Scratch big.Int values reused across polynomial evaluations.
+	// Each value is owned by one call between get and put.
+	pool sync.Pool
 }
 
 // NewShamirSecretSharing creates a new instance
-func NewShamirSecretSharing(threshold, numShares int) *ShamirSecretSharing {
-	if threshold > numShares {
-		panic("Threshold cannot be greater than number of shares")
+func NewShamirSecretSharing(threshold, numShares int) (*ShamirSecretSharing, error) {
+	if threshold < 1 || threshold > numShares {
+		return nil, fmt.Errorf("%w: threshold %d with %d shares", ErrInvalidThreshold, threshold, numShares)
+	}
+	// x-coordinates 1..numShares must stay distinct and non-zero modulo PRIME
+	if big.NewInt(int64(numShares)).Cmp(new(big.Int).Sub(PRIME, big.NewInt(1))) > 0 {
+		return nil, fmt.Errorf("%w: %d shares", ErrTooManyShares, numShares)
 	}
 	return &ShamirSecretSharing{
 		threshold: threshold,
 		numShares: numShares,
+		prime:     new(big.Int).Set(PRIME),
+		pool: sync.Pool{
+			New: func() any { return new(big.Int) },
+		},
+	}, nil
+}
+
+// getBigInt takes a zeroed big.Int from the pool
+func (sss *ShamirSecretSharing) getBigInt() *big.Int {
+	return sss.pool.Get().(*big.Int)
+}
+
+// putBigInt wipes a big.Int and returns it to the pool. Pooled values include
+// the secret and the polynomial coefficients, so every word of the backing
+// array is cleared, not just the length: SetInt64(0) alone would leave the
+// old words in memory for the next user of the pool.
+func (sss *ShamirSecretSharing) putBigInt(x *big.Int) {
+	words := x.Bits()
+	clear(words[:cap(words)])
+	x.SetInt64(0)
+	sss.pool.Put(x)
+}
+
+// SetProgress registers a callback invoked as image operations advance
+func (sss *ShamirSecretSharing) SetProgress(fn func(done, total int)) {
+	sss.progress = fn
+}
+
+// SetInterpolation selects the reconstruction algorithm
+func (sss *ShamirSecretSharing) SetInterpolation(method InterpolationMethod) {
+	sss.interpolation = method
+}
+
+// SetXOffset shifts the x-coordinates of generated shares to
+// offset+1..offset+numShares, so shares from different dealers stay distinct.
+// The offset must not be negative.
+func (sss *ShamirSecretSharing) SetXOffset(offset int) {
+	sss.xOffset = offset
+}
+
+// SetPrime changes the field this instance shares over. Shares only
+// reconstruct under the prime they were generated with, and every secret
+// must be below it.
+func (sss *ShamirSecretSharing) SetPrime(prime *big.Int) error {
+	if prime == nil || !prime.ProbablyPrime(32) {
+		return fmt.Errorf("%w: %v", ErrInvalidPrime, prime)
+	}
+	if big.NewInt(int64(sss.xOffset+sss.numShares)).Cmp(prime) >= 0 {
+		return fmt.Errorf("%w: %d shares with prime %s", ErrTooManyShares, sss.numShares, prime)
+	}
+	sss.prime = new(big.Int).Set(prime)
+	return nil
+}
+
+// Prime returns the field prime used by this instance
+func (sss *ShamirSecretSharing) Prime() *big.Int {
+	return new(big.Int).Set(sss.prime)
+}
+
+// SetConcurrency caps the worker goroutines used by the concurrent
+// operations. Zero means runtime.NumCPU(); 1 runs them sequentially.
+func (sss *ShamirSecretSharing) SetConcurrency(n int) {
+	sss.concurrency = n
+}
+
+// SetScheme chooses how secrets are split. SchemeXOR is faster but only
+// allowed when threshold == numShares, and its shares reconstruct only on an
+// instance set to SchemeXOR; record it in the share file header so readers
+// know (see ShareMetadata.Scheme).
+func (sss *ShamirSecretSharing) SetScheme(scheme Scheme) error {
+	switch scheme {
+	case SchemePolynomial:
+	case SchemeXOR:
+		if sss.threshold != sss.numShares {
+			return fmt.Errorf("%w: XOR splitting needs every share, not %d of %d", ErrInvalidThreshold, sss.threshold, sss.numShares)
+		}
+	default:
+		return fmt.Errorf("unknown sharing scheme %d", int(scheme))
+	}
+	sss.scheme = scheme
+	return nil
+}
+
+// Scheme returns the sharing scheme used by this instance
+func (sss *ShamirSecretSharing) Scheme() Scheme {
+	return sss.scheme
+}
+
+// SetConversion chooses how 8-bit image sharing turns each pixel into the
+// shared value, for example taking only the red channel or averaging the
+// channels. nil restores GrayModelConversion. 16-bit grayscale images are
+// shared at full depth by ShareImageWithDepth and are not converted.
+func (sss *ShamirSecretSharing) SetConversion(fn ConversionFunc) {
+	sss.conversion = fn
+}
+
+// workers returns how many goroutines to use for jobs items
+func (sss *ShamirSecretSharing) workers(jobs int) int {
+	n := sss.concurrency
+	if n <= 0 {
+		n = runtime.NumCPU()
+	}
+	return max(1, min(n, jobs))
+}
+
+// parallelFor runs fn for every index in [0, n) across sss.workers(n) goroutines
+func (sss *ShamirSecretSharing) parallelFor(n int, fn func(i int)) {
+	workers := sss.workers(n)
+	if workers == 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// Each worker takes every workers-th index
+			for i := w; i < n; i += workers {
+				fn(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// reportProgress forwards progress to the registered callback, if any
+func (sss *ShamirSecretSharing) reportProgress(done, total int) {
+	if sss.progress != nil {
+		sss.progress(done, total)
 	}
 }
 
@@ -55,11 +356,11 @@ func modInverse(a, m *big.Int) *big.Int {
 // generateRandomCoefficients generates random coefficients for the polynomial
 func (sss *ShamirSecretSharing) generateRandomCoefficients(secret *big.Int) []*big.Int {
 	coefficients := make([]*big.Int, sss.threshold)
-	coefficients[0] = new(big.Int).Set(secret) // a0 = secret
+	coefficients[0] = sss.getBigInt().Set(secret) // a0 = secret
 
 	for i := 1; i < sss.threshold; i++ {
 		// Generate random coefficient
-		coeff, err := rand.Int(rand.Reader, PRIME)
+		coeff, err := rand.Int(rand.Reader, sss.prime)
 		if err != nil {
 			panic("Failed to generate random coefficient")
 		}
@@ -72,25 +373,88 @@ func (sss *ShamirSecretSharing) generateRandomCoefficients(secret *big.Int) []*b
 // evaluatePolynomial evaluates polynomial at given x
 func (sss *ShamirSecretSharing) evaluatePolynomial(coefficients []*big.Int, x int) *big.Int {
 	result := new(big.Int).Set(coefficients[0])
-	xBig := big.NewInt(int64(x))
-	xPower := big.NewInt(1)
+	xBig := sss.getBigInt().SetInt64(int64(x))
+	xPower := sss.getBigInt().SetInt64(1)
+	term := sss.getBigInt()
+	defer func() {
+		sss.putBigInt(xBig)
+		sss.putBigInt(xPower)
+		sss.putBigInt(term)
+	}()
 
 	for i := 1; i < len(coefficients); i++ {
 		xPower.Mul(xPower, xBig)
-		term := new(big.Int).Mul(coefficients[i], xPower)
+		term.Mul(coefficients[i], xPower)
 		result.Add(result, term)
 	}
 
-	return result.Mod(result, PRIME)
+	return result.Mod(result, sss.prime)
+}
+
+// IsRedundant reports whether any share can be lost without losing the
+// secret. With numShares == threshold every share is mandatory.
+func (sss *ShamirSecretSharing) IsRedundant() bool {
+	return sss.numShares > sss.threshold
+}
+
+// sharesNeeded is the number of shares reconstruction consumes
+func (sss *ShamirSecretSharing) sharesNeeded() int {
+	if sss.usesXOR() {
+		return sss.numShares
+	}
+	return sss.threshold
+}
+
+// usesXOR reports whether shares are an XOR split rather than polynomial points
+func (sss *ShamirSecretSharing) usesXOR() bool {
+	return sss.scheme == SchemeXOR
+}
+
+// xorSplit splits a secret into random masks that XOR back to the secret.
+// The masks span the bit width of the prime rather than the field itself.
+func (sss *ShamirSecretSharing) xorSplit(secret, prime *big.Int) []Point {
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(prime.BitLen()))
+	shares := make([]Point, sss.numShares)
+	last := new(big.Int).Set(secret)
+
+	for i := 0; i < sss.numShares-1; i++ {
+		mask, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			panic("Failed to generate random mask")
+		}
+		last.Xor(last, mask)
+		shares[i] = Point{X: big.NewInt(int64(sss.xOffset + i + 1)), Y: mask}
+	}
+	shares[sss.numShares-1] = Point{X: big.NewInt(int64(sss.xOffset + sss.numShares)), Y: last}
+
+	return shares
+}
+
+// xorCombine reverses xorSplit by XOR-ing every share together
+func (sss *ShamirSecretSharing) xorCombine(points []Point) *big.Int {
+	if len(points) < sss.numShares {
+		panic("Insufficient shares to reconstruct secret")
+	}
+
+	secret := big.NewInt(0)
+	for _, p := range points[:sss.numShares] {
+		secret.Xor(secret, p.Y)
+	}
+
+	return secret
 }
 
 // GenerateShares creates shares for a secret
 func (sss *ShamirSecretSharing) GenerateShares(secret *big.Int) []Point {
+	if sss.usesXOR() {
+		return sss.xorSplit(secret, sss.prime)
+	}
+
 	coefficients := sss.generateRandomCoefficients(secret)
 	shares := make([]Point, sss.numShares)
 
 	for i := 0; i < sss.numShares; i++ {
-		x := i + 1 // x cannot be 0
+		x := sss.xOffset + i + 1 // x cannot be 0
 		y := sss.evaluatePolynomial(coefficients, x)
 		shares[i] = Point{
 			X: big.NewInt(int64(x)),
@@ -98,6 +462,11 @@ func (sss *ShamirSecretSharing) GenerateShares(secret *big.Int) []Point {
 		}
 	}
 
+	// Coefficients are no longer needed; recycle them as scratch space
+	for _, c := range coefficients {
+		sss.putBigInt(c)
+	}
+
 	return shares
 }
 
@@ -108,11 +477,24 @@ func (sss *ShamirSecretSharing) lagrangeInterpolation(points []Point) *big.Int {
 	}
 
 	// Take only threshold number of points
-	points = points[:sss.threshold]
+	return lagrangeAtZero(points[:sss.threshold], sss.prime)
+}
 
+// lagrangeAtZero evaluates the polynomial through the points at x = 0
+func lagrangeAtZero(points []Point, prime *big.Int) *big.Int {
+	secret, _ := lagrangeAtZeroContext(context.Background(), points, prime)
+	return secret
+}
+
+// lagrangeAtZeroContext is lagrangeAtZero that stops with ctx.Err() between
+// basis polynomials once ctx is done
+func lagrangeAtZeroContext(ctx context.Context, points []Point, prime *big.Int) (*big.Int, error) {
 	secret := big.NewInt(0)
 
 	for i := 0; i < len(points); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		xi := points[i].X
 		yi := points[i].Y
 
@@ -135,39 +517,144 @@ func (sss *ShamirSecretSharing) lagrangeInterpolation(points []Point) *big.Int {
 		}
 
 		// Calculate numerator / denominator mod prime
-		denominator.Mod(denominator, PRIME)
+		denominator.Mod(denominator, prime)
 		if denominator.Cmp(big.NewInt(0)) < 0 {
-			denominator.Add(denominator, PRIME)
+			denominator.Add(denominator, prime)
 		}
 
-		inv := modInverse(denominator, PRIME)
+		inv := modInverse(denominator, prime)
 		lagrangeBasis := new(big.Int).Mul(numerator, inv)
-		lagrangeBasis.Mod(lagrangeBasis, PRIME)
+		lagrangeBasis.Mod(lagrangeBasis, prime)
 
 		// Add yi * lagrangeBasis to secret
 		term := new(big.Int).Mul(yi, lagrangeBasis)
 		secret.Add(secret, term)
 	}
 
-	secret.Mod(secret, PRIME)
+	secret.Mod(secret, prime)
 	if secret.Cmp(big.NewInt(0)) < 0 {
-		secret.Add(secret, PRIME)
+		secret.Add(secret, prime)
 	}
 
+	return secret, nil
+}
+
+// newtonInterpolation reconstructs secret using Newton's divided differences
+func (sss *ShamirSecretSharing) newtonInterpolation(points []Point) *big.Int {
+	if len(points) < sss.threshold {
+		panic("Insufficient shares to reconstruct secret")
+	}
+
+	// Take only threshold number of points
+	secret, _ := sss.newtonAtZeroContext(context.Background(), points[:sss.threshold])
 	return secret
 }
 
+// newtonAtZeroContext evaluates the Newton form through the points at x = 0,
+// stopping with ctx.Err() between passes once ctx is done
+func (sss *ShamirSecretSharing) newtonAtZeroContext(ctx context.Context, points []Point) (*big.Int, error) {
+	n := len(points)
+
+	// Divided differences computed in place: after pass k,
+	// coeffs[i] holds f[x_{i-k}, ..., x_i]
+	coeffs := make([]*big.Int, n)
+	for i := range points {
+		coeffs[i] = new(big.Int).Mod(points[i].Y, sss.prime)
+	}
+
+	for k := 1; k < n; k++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i := n - 1; i >= k; i-- {
+			numerator := new(big.Int).Sub(coeffs[i], coeffs[i-1])
+			denominator := new(big.Int).Sub(points[i].X, points[i-k].X)
+			denominator.Mod(denominator, sss.prime)
+
+			coeffs[i] = numerator.Mul(numerator, modInverse(denominator, sss.prime))
+			coeffs[i].Mod(coeffs[i], sss.prime)
+		}
+	}
+
+	// Evaluate the Newton form at x = 0 using Horner's scheme
+	secret := new(big.Int).Set(coeffs[n-1])
+	for i := n - 2; i >= 0; i-- {
+		secret.Mul(secret, new(big.Int).Neg(points[i].X))
+		secret.Add(secret, coeffs[i])
+		secret.Mod(secret, sss.prime)
+	}
+
+	return secret, nil
+}
+
 // ReconstructSecret reconstructs the original secret from shares
 func (sss *ShamirSecretSharing) ReconstructSecret(shares []Point) *big.Int {
+	if sss.usesXOR() {
+		return sss.xorCombine(shares)
+	}
+	if sss.interpolation == InterpolationNewton {
+		return sss.newtonInterpolation(shares)
+	}
 	return sss.lagrangeInterpolation(shares)
 }
 
+// reconstructSecretContext is ReconstructSecret that stops with ctx.Err()
+// once ctx is done. shares must already hold at least the threshold.
+func (sss *ShamirSecretSharing) reconstructSecretContext(ctx context.Context, shares []Point) (*big.Int, error) {
+	if sss.usesXOR() {
+		return sss.xorCombine(shares), nil
+	}
+	if sss.interpolation == InterpolationNewton {
+		return sss.newtonAtZeroContext(ctx, shares[:sss.threshold])
+	}
+	return lagrangeAtZeroContext(ctx, shares[:sss.threshold], sss.prime)
+}
+
+// ReconstructSecretVerbose reconstructs a secret and also returns the exact
+// shares that contributed, for auditing. Like ReconstructSecret it uses the
+// leading shares, so order the input to control which are used.
+func (sss *ShamirSecretSharing) ReconstructSecretVerbose(shares []Point) (*big.Int, []Point, error) {
+	return sss.reconstructSecretVerbose(context.Background(), shares)
+}
+
+// reconstructSecretVerbose is ReconstructSecretVerbose that stops with
+// ctx.Err() once ctx is done
+func (sss *ShamirSecretSharing) reconstructSecretVerbose(ctx context.Context, shares []Point) (*big.Int, []Point, error) {
+	needed := sss.sharesNeeded()
+	if len(shares) < needed {
+		return nil, nil, fmt.Errorf("%w: have %d, need %d", ErrInsufficientShares, len(shares), needed)
+	}
+
+	shares, err := DedupeShares(shares)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(shares) < needed {
+		return nil, nil, fmt.Errorf("%w: have %d distinct, need %d", ErrInsufficientShares, len(shares), needed)
+	}
+
+	used := shares[:needed]
+	if err := sss.checkSharePoints(used); err != nil {
+		return nil, nil, err
+	}
+
+	secret, err := sss.reconstructSecretContext(ctx, used)
+	if err != nil {
+		return nil, nil, err
+	}
+	return secret, append([]Point(nil), used...), nil
+}
+
 // Text processing functions
 func (sss *ShamirSecretSharing) ShareText(text string) ([][]Point, error) {
-	bytes := []byte(text)
-	allShares := make([][]Point, len(bytes))
+	return sss.ShareArbitraryBytes([]byte(text))
+}
 
-	for i, b := range bytes {
+// ShareArbitraryBytes shares binary data one byte at a time
+func (sss *ShamirSecretSharing) ShareArbitraryBytes(data []byte) ([][]Point, error) {
+	allShares := make([][]Point, len(data))
+
+	for i, b := range data {
 		secret := big.NewInt(int64(b))
 		shares := sss.GenerateShares(secret)
 		allShares[i] = shares
@@ -176,18 +663,229 @@ func (sss *ShamirSecretSharing) ShareText(text string) ([][]Point, error) {
 	return allShares, nil
 }
 
+// ShareTextConcurrent shares text like ShareText, splitting the characters
+// across worker goroutines (see SetConcurrency)
+func (sss *ShamirSecretSharing) ShareTextConcurrent(text string) ([][]Point, error) {
+	bytes := []byte(text)
+	allShares := make([][]Point, len(bytes))
+
+	sss.parallelFor(len(bytes), func(i int) {
+		allShares[i] = sss.GenerateShares(big.NewInt(int64(bytes[i])))
+	})
+
+	return allShares, nil
+}
+
+// ReconstructTextConcurrent is ReconstructText spread across worker goroutines
+func (sss *ShamirSecretSharing) ReconstructTextConcurrent(allShares [][]Point) (string, error) {
+	allShares, err := sss.prepareShares(allShares)
+	if err != nil {
+		return "", err
+	}
+
+	bytes := make([]byte, len(allShares))
+	sss.parallelFor(len(allShares), func(i int) {
+		bytes[i] = byte(sss.ReconstructSecret(allShares[i]).Int64())
+	})
+
+	return string(bytes), nil
+}
+
 func (sss *ShamirSecretSharing) ReconstructText(allShares [][]Point) (string, error) {
+	bytes, err := sss.ReconstructArbitraryBytes(allShares)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// MinSharesAvailable returns the smallest number of shares held for any
+// secret, or 0 when there are no secrets
+func MinSharesAvailable(allShares [][]Point) int {
+	if len(allShares) == 0 {
+		return 0
+	}
+	minimum := len(allShares[0])
+	for _, shares := range allShares[1:] {
+		minimum = min(minimum, len(shares))
+	}
+	return minimum
+}
+
+// DedupeShares drops repeated shares, as left by merging overlapping share
+// files, keeping the first share for each x-coordinate. Two shares with the
+// same x but different y give ErrInconsistentShares. The input is returned
+// unchanged when it has no repeats.
+func DedupeShares(shares []Point) ([]Point, error) {
+	// Share lists are short, so a pairwise scan beats hashing and allocates
+	// nothing in the common case of no repeats
+	var deduped []Point
+	for i, p := range shares {
+		if p.X == nil || p.Y == nil {
+			return nil, fmt.Errorf("%w: missing coordinate", ErrInvalidShare)
+		}
+		repeat := false
+		for _, q := range shares[:i] {
+			if q.X.Cmp(p.X) != 0 {
+				continue
+			}
+			if q.Y.Cmp(p.Y) != 0 {
+				return nil, fmt.Errorf("%w: x = %s", ErrInconsistentShares, p.X)
+			}
+			repeat = true
+			break
+		}
+		switch {
+		case repeat && deduped == nil:
+			deduped = append(make([]Point, 0, len(shares)-1), shares[:i]...)
+		case !repeat && deduped != nil:
+			deduped = append(deduped, p)
+		}
+	}
+	if deduped == nil {
+		return shares, nil
+	}
+	return deduped, nil
+}
+
+// prepareShares deduplicates each secret's shares and then checks there are
+// enough of them. The caller's slices are not modified.
+func (sss *ShamirSecretSharing) prepareShares(allShares [][]Point) ([][]Point, error) {
+	return sss.prepareSharesPrime(allShares, sss.prime)
+}
+
+// prepareSharesPrime is prepareShares for shares over prime rather than this
+// instance's field, as when migrating shares from another field
+func (sss *ShamirSecretSharing) prepareSharesPrime(allShares [][]Point, prime *big.Int) ([][]Point, error) {
+	prepared, copied := allShares, false
+	for i, shares := range allShares {
+		deduped, err := DedupeShares(shares)
+		if err != nil {
+			return nil, fmt.Errorf("secret %d: %w", i, err)
+		}
+		if len(deduped) == len(shares) {
+			continue
+		}
+		if !copied {
+			prepared, copied = append([][]Point(nil), allShares...), true
+		}
+		prepared[i] = deduped
+	}
+	if err := sss.checkSharesAvailable(prepared, prime); err != nil {
+		return nil, err
+	}
+	return prepared, nil
+}
+
+// checkSharesAvailable verifies every secret has enough valid shares over
+// prime to reconstruct
+func (sss *ShamirSecretSharing) checkSharesAvailable(allShares [][]Point, prime *big.Int) error {
+	needed := sss.sharesNeeded()
+	if have := MinSharesAvailable(allShares); len(allShares) > 0 && have < needed {
+		return fmt.Errorf("%w: some secrets have only %d shares, need %d", ErrInsufficientShares, have, needed)
+	}
+	for _, shares := range allShares {
+		if err := sss.checkSharePointsPrime(shares[:needed], prime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSharePoints rejects missing coordinates and x-coordinates outside
+// [1, prime). Larger x values would alias smaller ones mod the prime and leave
+// a zero Lagrange denominator. XOR masks may exceed the prime, so y is only
+// checked for polynomial shares.
+func (sss *ShamirSecretSharing) checkSharePoints(shares []Point) error {
+	return sss.checkSharePointsPrime(shares, sss.prime)
+}
+
+// checkSharePointsPrime is checkSharePoints for shares over prime
+func (sss *ShamirSecretSharing) checkSharePointsPrime(shares []Point, prime *big.Int) error {
+	for _, p := range shares {
+		if p.X == nil || p.Y == nil {
+			return fmt.Errorf("%w: missing coordinate", ErrInvalidShare)
+		}
+		if p.X.Sign() <= 0 || p.X.Cmp(prime) >= 0 {
+			return fmt.Errorf("%w: x = %s", ErrInvalidShareIndex, p.X)
+		}
+		if !sss.usesXOR() && !p.IsValid(prime) {
+			return fmt.Errorf("%w: y = %s is not reduced mod %s", ErrInvalidShare, p.Y, prime)
+		}
+	}
+	return nil
+}
+
+// ReconstructArbitraryBytes reverses ShareArbitraryBytes
+func (sss *ShamirSecretSharing) ReconstructArbitraryBytes(allShares [][]Point) ([]byte, error) {
+	return sss.reconstructArbitraryBytes(context.Background(), allShares)
+}
+
+// ReconstructBytesTo writes each reconstructed byte to w as soon as it is
+// recovered instead of buffering the whole result. Share counts are checked
+// before anything is written. It returns the number of bytes written.
+func (sss *ShamirSecretSharing) ReconstructBytesTo(w io.Writer, allShares [][]Point) (int64, error) {
+	allShares, err := sss.prepareShares(allShares)
+	if err != nil {
+		return 0, err
+	}
+
+	writer := bufio.NewWriter(w)
+	for _, shares := range allShares {
+		writer.WriteByte(byte(sss.ReconstructSecret(shares).Int64()))
+	}
+	if err := writer.Flush(); err != nil {
+		return 0, err
+	}
+	return int64(len(allShares)), nil
+}
+
+// reconstructArbitraryBytes stops early once ctx is done
+func (sss *ShamirSecretSharing) reconstructArbitraryBytes(ctx context.Context, allShares [][]Point) ([]byte, error) {
+	allShares, err := sss.prepareShares(allShares)
+	if err != nil {
+		return nil, err
+	}
+
 	bytes := make([]byte, len(allShares))
 
 	for i, shares := range allShares {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		secret := sss.ReconstructSecret(shares)
 		bytes[i] = byte(secret.Int64())
 	}
 
-	return string(bytes), nil
+	return bytes, nil
 }
 
-// Image processing functions
+// ShareTextWithNonce shares text and generates a fresh session nonce that
+// should be stored in the share file header
+func (sss *ShamirSecretSharing) ShareTextWithNonce(text string) ([][]Point, []byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	allShares, err := sss.ShareText(text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return allShares, nonce, nil
+}
+
+// ReconstructTextVerified reconstructs text only if the share file's nonce
+// matches the nonce expected for the current session
+func (sss *ShamirSecretSharing) ReconstructTextVerified(allShares [][]Point, nonce []byte, expectedNonce []byte) (string, error) {
+	if len(expectedNonce) == 0 || subtle.ConstantTimeCompare(nonce, expectedNonce) != 1 {
+		return "", ErrNonceMismatch
+	}
+	return sss.ReconstructText(allShares)
+}
+
+// ShareImage opens imagePath and shares it with ShareImageReader
 func (sss *ShamirSecretSharing) ShareImage(imagePath string) ([][]Point, int, int, error) {
 	file, err := os.Open(imagePath)
 	if err != nil {
@@ -195,11 +893,122 @@ func (sss *ShamirSecretSharing) ShareImage(imagePath string) ([][]Point, int, in
 	}
 	defer file.Close()
 
-	img, _, err := image.Decode(file)
+	return sss.ShareImageReader(file)
+}
+
+// ShareImageReader shares an image decoded from r in any registered format
+func (sss *ShamirSecretSharing) ShareImageReader(r io.Reader) ([][]Point, int, int, error) {
+	img, err := decodeImage(r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	pixels, width, height := grayPixels(img, sss.conversion)
+	return sss.sharePixels(pixels), width, height, nil
+}
+
+// loadGrayPixels decodes an image file into row-major grayscale pixel values
+// using convert, or GrayModelConversion if it is nil
+func loadGrayPixels(imagePath string, convert ConversionFunc) ([]uint8, int, int, error) {
+	img, err := decodeImageFile(imagePath)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 
+	pixels, width, height := grayPixels(img, convert)
+	return pixels, width, height, nil
+}
+
+// decodeImageFile opens and decodes an image in any registered format
+func decodeImageFile(imagePath string) (image.Image, error) {
+	img, _, err := decodeImageFileFormat(imagePath)
+	return img, err
+}
+
+// decodeImageFileFormat is decodeImageFile that also returns the name of the
+// format the file was encoded in
+func decodeImageFileFormat(imagePath string) (image.Image, string, error) {
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	return decodeImageFormat(file)
+}
+
+// decodeImage decodes an image after checking its declared size against
+// MaxImagePixels, so a decompression bomb is rejected before any pixel
+// memory is allocated
+func decodeImage(r io.Reader) (image.Image, error) {
+	img, _, err := decodeImageFormat(r)
+	return img, err
+}
+
+// decodeImageFormat is decodeImage that also returns the format name
+func decodeImageFormat(r io.Reader) (image.Image, string, error) {
+	// Keep the header bytes read by DecodeConfig so Decode can see them again
+	var header bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, "", err
+	}
+	if err := checkImageSize(config.Width, config.Height); err != nil {
+		return nil, "", err
+	}
+
+	img, format, err := image.Decode(io.MultiReader(&header, r))
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The header is only a claim; check what was actually decoded as well
+	bounds := img.Bounds()
+	if err := checkImageSize(bounds.Dx(), bounds.Dy()); err != nil {
+		return nil, "", err
+	}
+	return img, format, nil
+}
+
+// checkImageSize enforces MaxImagePixels
+func checkImageSize(width, height int) error {
+	if MaxImagePixels > 0 && int64(width)*int64(height) > MaxImagePixels {
+		return fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrImageTooLarge, width, height, MaxImagePixels)
+	}
+	return nil
+}
+
+// Pixels sampled by isGrayscale; enough to catch color in ordinary images
+// without walking every pixel of a large one
+const grayscaleSamples = 4096
+
+// isGrayscale reports whether an image appears to hold no color. Gray image
+// types are trusted; for others up to grayscaleSamples pixels spread evenly
+// over the image are checked for equal red, green and blue.
+func isGrayscale(img image.Image) bool {
+	switch img.(type) {
+	case *image.Gray, *image.Gray16:
+		return true
+	}
+
+	bounds := img.Bounds()
+	total := bounds.Dx() * bounds.Dy()
+	step := max(1, total/grayscaleSamples)
+	for i := 0; i < total; i += step {
+		r, g, b, _ := img.At(bounds.Min.X+i%bounds.Dx(), bounds.Min.Y+i/bounds.Dx()).RGBA()
+		if r != g || g != b {
+			return false
+		}
+	}
+	return true
+}
+
+// grayPixels converts an image into row-major 8-bit grayscale values using
+// convert, or GrayModelConversion if it is nil
+func grayPixels(img image.Image, convert ConversionFunc) ([]uint8, int, int) {
+	if convert == nil {
+		convert = GrayModelConversion
+	}
 	bounds := img.Bounds()
 	width, height := bounds.Dx(), bounds.Dy()
 
@@ -209,29 +1018,37 @@ func (sss *ShamirSecretSharing) ShareImage(imagePath string) ([][]Point, int, in
 
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			c := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
-			pixels[idx] = c.Y
+			pixels[idx] = convert(img.At(x, y))
 			idx++
 		}
 	}
 
-	// Generate shares for each pixel
+	return pixels, width, height
+}
+
+// sharePixels generates shares for each pixel value
+func (sss *ShamirSecretSharing) sharePixels(pixels []uint8) [][]Point {
 	allShares := make([][]Point, len(pixels))
 	for i, pixel := range pixels {
 		secret := big.NewInt(int64(pixel))
 		shares := sss.GenerateShares(secret)
 		allShares[i] = shares
+		sss.reportProgress(i+1, len(pixels))
 	}
 
-	return allShares, width, height, nil
+	return allShares
 }
 
-func (sss *ShamirSecretSharing) ReconstructImage(allShares [][]Point, width, height int, outputPath string) error {
-	// Reconstruct pixel values
-	pixels := make([]uint8, len(allShares))
-	for i, shares := range allShares {
-		secret := sss.ReconstructSecret(shares)
-		pixels[i] = uint8(secret.Int64())
+// ReconstructImage rebuilds a grayscale image from its pixel shares
+func (sss *ShamirSecretSharing) ReconstructImage(allShares [][]Point, width, height int) (image.Image, error) {
+	return sss.reconstructImage(context.Background(), allShares, width, height)
+}
+
+// reconstructImage stops early once ctx is done
+func (sss *ShamirSecretSharing) reconstructImage(ctx context.Context, allShares [][]Point, width, height int) (image.Image, error) {
+	pixels, err := sss.reconstructImageBytes(ctx, allShares, width, height)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create image
@@ -245,75 +1062,263 @@ func (sss *ShamirSecretSharing) ReconstructImage(allShares [][]Point, width, hei
 		}
 	}
 
-	// Save image
+	return img, nil
+}
+
+// ReconstructImageWriter encodes a reconstructed image to w as PNG
+func ReconstructImageWriter(img image.Image, w io.Writer) error {
+	return png.Encode(w, img)
+}
+
+// writePNGFile saves an image as a PNG file
+func writePNGFile(img image.Image, outputPath string) error {
 	file, err := os.Create(outputPath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	if err := ReconstructImageWriter(img, file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// ReconstructImageBytes reconstructs the row-major grayscale pixel values
+// without encoding them into an image file
+func (sss *ShamirSecretSharing) ReconstructImageBytes(allShares [][]Point, width, height int) ([]byte, error) {
+	return sss.reconstructImageBytes(context.Background(), allShares, width, height)
+}
+
+// reconstructImageBytes stops early once ctx is done
+func (sss *ShamirSecretSharing) reconstructImageBytes(ctx context.Context, allShares [][]Point, width, height int) ([]byte, error) {
+	if len(allShares) != width*height {
+		return nil, fmt.Errorf("%w: have shares for %d pixels, a %dx%d image has %d",
+			ErrDimensionMismatch, len(allShares), width, height, width*height)
+	}
+	allShares, err := sss.prepareShares(allShares)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reconstruct pixel values
+	pixels := make([]byte, len(allShares))
+	for i, shares := range allShares {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		secret := sss.ReconstructSecret(shares)
+		pixels[i] = uint8(secret.Int64())
+		sss.reportProgress(i+1, len(allShares))
+	}
 
-	return png.Encode(file, img)
+	return pixels, nil
 }
 
 // Utility functions for saving/loading shares
 func saveTextShares(allShares [][]Point, filename string) error {
+	return saveTextSharesMeta(allShares, ShareMetadata{}, filename)
+}
+
+// saveTextSharesMeta saves text shares preceded by a metadata header
+func saveTextSharesMeta(allShares [][]Point, meta ShareMetadata, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
+	return writeTextShares(file, allShares, meta)
+}
+
+// writeTextShares writes shares in the text share file format
+func writeTextShares(w io.Writer, allShares [][]Point, meta ShareMetadata) error {
+	writer := bufio.NewWriter(w)
+
+	writeShareHeader(writer, meta)
 
 	// Write number of characters
 	fmt.Fprintf(writer, "%d\n", len(allShares))
+	writeShareBlocks(writer, allShares)
+
+	return writer.Flush()
+}
 
-	// Write shares for each character
+// writeShareBlocks writes each secret's share count followed by its shares
+func writeShareBlocks(w io.Writer, allShares [][]Point) {
 	for _, shares := range allShares {
-		fmt.Fprintf(writer, "%d\n", len(shares))
+		fmt.Fprintf(w, "%d\n", len(shares))
 		for _, share := range shares {
-			fmt.Fprintf(writer, "%s %s\n", share.X.String(), share.Y.String())
+			fmt.Fprintf(w, "%s %s\n", share.X.String(), share.Y.String())
 		}
 	}
-
-	return nil
 }
 
 func loadTextShares(filename string) ([][]Point, error) {
+	allShares, _, err := loadTextSharesMeta(filename)
+	return allShares, err
+}
+
+// loadTextSharesMeta loads text shares along with their metadata header
+func loadTextSharesMeta(filename string) ([][]Point, ShareMetadata, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, err
+		return nil, ShareMetadata{}, err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	allShares, meta, _, err := readTextShares(file)
+	if err != nil {
+		return nil, ShareMetadata{}, err
+	}
+	if err := checkShareCounts(allShares, meta.Threshold); err != nil {
+		return nil, ShareMetadata{}, err
+	}
+	return allShares, meta, nil
+}
 
-	// Read number of characters
-	scanner.Scan()
-	numChars, _ := strconv.Atoi(scanner.Text())
+// loadTextSharesPartial loads as many complete characters as possible from a
+// share file. Alongside the complete prefix it returns how many characters
+// were read and, if the file ended early, an error naming the first
+// incomplete character index.
+func loadTextSharesPartial(filename string) ([][]Point, int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	allShares, _, complete, err := readTextShares(file)
+	return allShares, complete, err
+}
+
+// newShareScanner returns a line scanner for share files that trims
+// surrounding whitespace from each line, so files edited or saved with CRLF
+// line endings on Windows parse like the originals
+func newShareScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if token == nil {
+			return advance, nil, err
+		}
+		// TrimSpace returns nil for blank lines, which the scanner would skip
+		if trimmed := bytes.TrimSpace(token); trimmed != nil {
+			token = trimmed
+		} else {
+			token = token[:0]
+		}
+		return advance, token, err
+	})
+	return scanner
+}
+
+// readTextShares parses a text share file, returning the complete prefix of
+// characters and how many were read even when the data ends early
+func readTextShares(r io.Reader) ([][]Point, ShareMetadata, int, error) {
+	scanner := newShareScanner(r)
 
-	allShares := make([][]Point, numChars)
+	meta, err := scanShareHeader(scanner)
+	if err != nil {
+		return nil, meta, 0, err
+	}
 
+	// Read number of characters
+	numChars, err := strconv.Atoi(scanner.Text())
+	if err != nil || numChars < 0 {
+		return nil, meta, 0, fmt.Errorf("invalid character count %q", scanner.Text())
+	}
+
+	// The count comes from the file, so grow as shares are read rather than
+	// trusting it for an allocation
+	var allShares [][]Point
 	for i := 0; i < numChars; i++ {
-		scanner.Scan()
-		numShares, _ := strconv.Atoi(scanner.Text())
+		shares, err := scanShares(scanner)
+		if scanErr := scanner.Err(); scanErr != nil {
+			return allShares, meta, i, fmt.Errorf("reading share file at character %d: %w", i, scanErr)
+		}
+		if err != nil {
+			return allShares, meta, i, fmt.Errorf("character %d of %d: %w", i, numChars, err)
+		}
+		allShares = append(allShares, shares)
+	}
+
+	return allShares, meta, numChars, nil
+}
 
-		shares := make([]Point, numShares)
-		for j := 0; j < numShares; j++ {
-			scanner.Scan()
-			parts := strings.Split(scanner.Text(), " ")
-			x, _ := new(big.Int).SetString(parts[0], 10)
-			y, _ := new(big.Int).SetString(parts[1], 10)
-			shares[j] = Point{X: x, Y: y}
+// scanStopped explains why a scanner stopped before an expected line: the
+// underlying read error if there was one, otherwise ErrTruncatedShares
+func scanStopped(scanner *bufio.Scanner, missing string) error {
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrTruncatedShares, missing)
+}
+
+// checkShareCounts verifies every secret in a combined share file has at
+// least the threshold recorded in its header. Files may legitimately hold
+// uneven counts, for example after merging partial participant files, so
+// only counts that make reconstruction impossible are rejected. A threshold
+// of 0 means none was recorded and is left to reconstruction to check.
+func checkShareCounts(allShares [][]Point, threshold int) error {
+	for i, shares := range allShares {
+		if len(shares) < threshold {
+			return fmt.Errorf("%w: secret %d has %d shares, header threshold is %d",
+				ErrInsufficientShares, i, len(shares), threshold)
 		}
-		allShares[i] = shares
+	}
+	return nil
+}
+
+// scanShares reads a share count line followed by that many "x y" lines
+func scanShares(scanner *bufio.Scanner) ([]Point, error) {
+	if !scanner.Scan() {
+		return nil, scanStopped(scanner, "missing share count")
+	}
+	numShares, err := strconv.Atoi(scanner.Text())
+	if err != nil || numShares < 0 {
+		return nil, fmt.Errorf("invalid share count %q", scanner.Text())
 	}
 
-	return allShares, nil
+	var shares []Point
+	for j := 0; j < numShares; j++ {
+		if !scanner.Scan() {
+			return nil, scanStopped(scanner, fmt.Sprintf("missing share %d of %d", j+1, numShares))
+		}
+		share, err := parsePointLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("share %d: %w", j+1, err)
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// parsePointLine parses a single "x y" share line
+func parsePointLine(line string) (Point, error) {
+	parts := strings.Split(line, " ")
+	if len(parts) != 2 || parts[1] == "" {
+		return Point{}, fmt.Errorf("%w: incomplete share line %q", ErrTruncatedShares, line)
+	}
+
+	x, ok := new(big.Int).SetString(parts[0], 10)
+	if !ok {
+		return Point{}, fmt.Errorf("invalid x value %q", parts[0])
+	}
+	y, ok := new(big.Int).SetString(parts[1], 10)
+	if !ok {
+		return Point{}, fmt.Errorf("invalid y value %q", parts[1])
+	}
+
+	return Point{X: x, Y: y}, nil
 }
 
 func saveImageShares(allShares [][]Point, width, height int, filename string) error {
+	return saveImageSharesMeta(allShares, width, height, ShareMetadata{}, filename)
+}
+
+// saveImageSharesMeta saves image shares preceded by a metadata header
+func saveImageSharesMeta(allShares [][]Point, width, height int, meta ShareMetadata, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
@@ -323,6 +1328,8 @@ func saveImageShares(allShares [][]Point, width, height int, filename string) er
 	writer := bufio.NewWriter(file)
 	defer writer.Flush()
 
+	writeShareHeader(writer, meta)
+
 	// Write image dimensions and number of pixels
 	fmt.Fprintf(writer, "%d %d %d\n", width, height, len(allShares))
 
@@ -338,57 +1345,138 @@ func saveImageShares(allShares [][]Point, width, height int, filename string) er
 }
 
 func loadImageShares(filename string) ([][]Point, int, int, error) {
+	allShares, width, height, _, err := loadImageSharesMeta(filename)
+	return allShares, width, height, err
+}
+
+// loadImageSharesMeta loads image shares along with their metadata header
+func loadImageSharesMeta(filename string) ([][]Point, int, int, ShareMetadata, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, 0, 0, err
+		return nil, 0, 0, ShareMetadata{}, err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	scanner := newShareScanner(file)
 
-	// Read dimensions and number of pixels
-	scanner.Scan()
-	parts := strings.Split(scanner.Text(), " ")
-	width, _ := strconv.Atoi(parts[0])
-	height, _ := strconv.Atoi(parts[1])
-	numPixels, _ := strconv.Atoi(parts[2])
+	meta, err := scanShareHeader(scanner)
+	if err != nil {
+		return nil, 0, 0, meta, err
+	}
 
-	allShares := make([][]Point, numPixels)
+	// Read dimensions and number of pixels
+	var width, height, numPixels int
+	if _, err := fmt.Sscanf(scanner.Text(), "%d %d %d", &width, &height, &numPixels); err != nil ||
+		width < 0 || height < 0 || numPixels < 0 {
+		return nil, 0, 0, meta, fmt.Errorf("invalid image dimensions %q", scanner.Text())
+	}
+	if int64(numPixels) != int64(width)*int64(height) {
+		return nil, 0, 0, meta, fmt.Errorf("%w: %d pixels declared for a %dx%d image",
+			ErrDimensionMismatch, numPixels, width, height)
+	}
 
+	// As in readTextShares, a huge pixel count must not allocate up front
+	var allShares [][]Point
 	for i := 0; i < numPixels; i++ {
-		scanner.Scan()
-		numShares, _ := strconv.Atoi(scanner.Text())
-
-		shares := make([]Point, numShares)
-		for j := 0; j < numShares; j++ {
-			scanner.Scan()
-			parts := strings.Split(scanner.Text(), " ")
-			x, _ := new(big.Int).SetString(parts[0], 10)
-			y, _ := new(big.Int).SetString(parts[1], 10)
-			shares[j] = Point{X: x, Y: y}
+		shares, err := scanShares(scanner)
+		if scanErr := scanner.Err(); scanErr != nil {
+			return nil, 0, 0, meta, fmt.Errorf("reading share file at pixel %d: %w", i, scanErr)
 		}
-		allShares[i] = shares
+		if errors.Is(err, ErrTruncatedShares) {
+			return nil, 0, 0, meta, fmt.Errorf("%w: read %d of %d pixels: %w", io.ErrUnexpectedEOF, i, numPixels, err)
+		}
+		if err != nil {
+			return nil, 0, 0, meta, fmt.Errorf("pixel %d of %d: %w", i, numPixels, err)
+		}
+		allShares = append(allShares, shares)
+	}
+	if err := checkShareCounts(allShares, meta.Threshold); err != nil {
+		return nil, 0, 0, meta, err
 	}
 
-	return allShares, width, height, nil
+	return allShares, width, height, meta, nil
 }
 
 func main() {
+	quiet := flag.Bool("quiet", false, "suppress progress output")
+	outputFormat := flag.String("output-format", "text", "format for text shares: text or env")
+	thresholdFlag := flag.String("threshold", "", "threshold as a number, a percentage of the shares (60%) or majority")
+	serve := flag.String("serve", "", "serve the web UI and JSON API on this address instead of running the menu")
+	xOffset := flag.Int("xoffset", 0, "shift share x-coordinates to avoid collisions with other dealers")
+	shuffle := flag.Bool("shuffle", false, "randomize the order of each secret's shares in saved share files")
+	ascii := flag.Bool("ascii", false, "show reconstructed images in the terminal as ASCII art instead of saving them")
+	minShares := flag.Int("min-shares", DefaultMinShares, "smallest number of shares accepted without -force")
+	force := flag.Bool("force", false, "allow generating fewer shares than -min-shares")
+	field := flag.String("field", "prime31", "prime field preset: "+strings.Join(FieldNames(), ", "))
+	xorSplit := flag.Bool("xor", false, "split with XOR masks instead of a polynomial; needs threshold equal to the number of shares")
+	secretFD := flag.Int("secret-fd", -1, "read the text to share from this file descriptor instead of prompting")
+	flag.Parse()
+	if *xOffset < 0 {
+		fmt.Println("Error: -xoffset must not be negative")
+		os.Exit(2)
+	}
+	if *outputFormat != "text" && *outputFormat != "env" {
+		fmt.Printf("Error: unknown output format %q\n", *outputFormat)
+		os.Exit(2)
+	}
+	// Only share file headers record the scheme, so XOR shares need them
+	if *xorSplit && *outputFormat != "text" {
+		fmt.Println("Error: -xor needs the text output format")
+		os.Exit(2)
+	}
+
+	if *serve != "" {
+		fmt.Printf("Serving on %s\n", *serve)
+		if err := RunServer(*serve); err != nil {
+			fmt.Printf("Server error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 
 	fmt.Println("Shamir's Secret Sharing Implementation")
 	fmt.Println("=====================================")
 
-	// Get parameters
-	fmt.Print("Enter threshold (minimum shares needed to reconstruct): ")
-	thresholdStr, _ := reader.ReadString('\n')
-	threshold, _ := strconv.Atoi(strings.TrimSpace(thresholdStr))
-
+	// Get parameters; the share count comes first so relative thresholds can be resolved
 	fmt.Print("Enter total number of shares to generate: ")
 	numSharesStr, _ := reader.ReadString('\n')
 	numShares, _ := strconv.Atoi(strings.TrimSpace(numSharesStr))
+	if err := checkMinShares(numShares, *minShares, *force); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(2)
+	}
 
-	sss := NewShamirSecretSharing(threshold, numShares)
+	thresholdSpec := *thresholdFlag
+	if thresholdSpec == "" {
+		fmt.Print("Enter threshold (a number, a percentage such as 60%, or majority): ")
+		thresholdSpec, _ = reader.ReadString('\n')
+	}
+	threshold, err := ParseThreshold(thresholdSpec, numShares)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	sss, err := NewShamirSecretSharingWithField(threshold, numShares, *field)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(2)
+	}
+	sss.SetXOffset(*xOffset)
+	if *xorSplit {
+		if err := sss.SetScheme(SchemeXOR); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	for _, warning := range AnalyzeParameters(threshold, numShares, sss.Prime()).Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	if !*quiet && isTerminal(os.Stdout) {
+		sss.SetProgress(newProgressPrinter(os.Stdout))
+	}
 
 	// Choose operation
 	fmt.Println("\nChoose operation:")
@@ -396,7 +1484,10 @@ func main() {
 	fmt.Println("2. Reconstruct text")
 	fmt.Println("3. Share image")
 	fmt.Println("4. Reconstruct image")
-	fmt.Print("Enter choice (1-4): ")
+	fmt.Println("5. Show share file info")
+	fmt.Println("6. Run self-test")
+	fmt.Println("7. Verify shares against an original file")
+	fmt.Print("Enter choice (1-7): ")
 
 	choiceStr, _ := reader.ReadString('\n')
 	choice, _ := strconv.Atoi(strings.TrimSpace(choiceStr))
@@ -404,11 +1495,19 @@ func main() {
 	switch choice {
 	case 1:
 		// Share text
-		fmt.Print("Enter text to share: ")
-		text, _ := reader.ReadString('\n')
-		text = strings.TrimSpace(text)
+		var text string
+		if *secretFD >= 0 {
+			if text, err = readFD(*secretFD); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		} else {
+			fmt.Print("Enter text to share: ")
+			text, _ = reader.ReadString('\n')
+			text = strings.TrimSpace(text)
+		}
 
-		allShares, err := sss.ShareText(text)
+		allShares, nonce, err := sss.ShareTextWithNonce(text)
 		if err != nil {
 			fmt.Printf("Error sharing text: %v\n", err)
 			return
@@ -418,7 +1517,35 @@ func main() {
 		filename, _ := reader.ReadString('\n')
 		filename = strings.TrimSpace(filename)
 
-		err = saveTextShares(allShares, filename)
+		if *outputFormat == "env" {
+			if err := saveEnvFile(FormatSharesAsEnvVars(allShares, "SSS"), filename); err != nil {
+				fmt.Printf("Error saving shares: %v\n", err)
+				return
+			}
+			fmt.Printf("Text shares saved to %s as environment variables\n", filename)
+			fmt.Printf("Generated %d shares for %d characters\n", numShares, len(text))
+			return
+		}
+
+		fmt.Print("Enter an optional description for the share file: ")
+		description, _ := reader.ReadString('\n')
+
+		meta := ShareMetadata{
+			Digest:      MessageDigest([]byte(text)),
+			Nonce:       nonce,
+			Threshold:   threshold,
+			XOffset:     *xOffset,
+			Scheme:      sss.Scheme(),
+			Prime:       sss.Prime(),
+			Description: strings.TrimSpace(description),
+		}
+		if *shuffle {
+			if allShares, err = ShuffleShares(allShares); err != nil {
+				fmt.Printf("Error shuffling shares: %v\n", err)
+				return
+			}
+		}
+		err = saveTextSharesMeta(allShares, meta, filename)
 		if err != nil {
 			fmt.Printf("Error saving shares: %v\n", err)
 			return
@@ -426,6 +1553,7 @@ func main() {
 
 		fmt.Printf("Text shares saved to %s\n", filename)
 		fmt.Printf("Generated %d shares for %d characters\n", numShares, len(text))
+		fmt.Printf("Session nonce: %s\n", hex.EncodeToString(nonce))
 
 	case 2:
 		// Reconstruct text
@@ -433,18 +1561,38 @@ func main() {
 		filename, _ := reader.ReadString('\n')
 		filename = strings.TrimSpace(filename)
 
-		allShares, err := loadTextShares(filename)
+		allShares, meta, err := loadTextSharesMeta(filename)
 		if err != nil {
 			fmt.Printf("Error loading shares: %v\n", err)
 			return
 		}
+		if err := sss.applyShareMetadata(meta); err != nil {
+			fmt.Printf("Error loading shares: %v\n", err)
+			return
+		}
 
-		reconstructedText, err := sss.ReconstructText(allShares)
+		var reconstructedText string
+		if meta.Nonce != nil {
+			fmt.Print("Enter expected session nonce: ")
+			expectedStr, _ := reader.ReadString('\n')
+			expected, _ := hex.DecodeString(strings.TrimSpace(expectedStr))
+
+			reconstructedText, err = sss.ReconstructTextVerified(allShares, meta.Nonce, expected)
+		} else {
+			reconstructedText, err = sss.ReconstructText(allShares)
+		}
 		if err != nil {
 			fmt.Printf("Error reconstructing text: %v\n", err)
 			return
 		}
 
+		if meta.Digest != nil {
+			if err := VerifyMessageDigest([]byte(reconstructedText), meta.Digest); err != nil {
+				fmt.Printf("Error verifying reconstructed text: %v\n", err)
+				return
+			}
+		}
+
 		fmt.Printf("Reconstructed text: %s\n", reconstructedText)
 
 	case 3:
@@ -453,17 +1601,59 @@ func main() {
 		imagePath, _ := reader.ReadString('\n')
 		imagePath = strings.TrimSpace(imagePath)
 
-		allShares, width, height, err := sss.ShareImage(imagePath)
+		img, format, err := decodeImageFileFormat(imagePath)
 		if err != nil {
 			fmt.Printf("Error sharing image: %v\n", err)
 			return
 		}
+		paletted, isPaletted := img.(*image.Paletted)
+		if !isPaletted && !*quiet && !isGrayscale(img) {
+			fmt.Println("Warning: the image has color, which will be discarded; only its grayscale version is shared")
+		}
 
 		fmt.Print("Enter filename to save image shares: ")
 		filename, _ := reader.ReadString('\n')
 		filename = strings.TrimSpace(filename)
+		// Sharing every pixel is slow, so find out now if the file can't be written
+		if err := checkWritable(filename); err != nil {
+			fmt.Printf("Error saving image shares: %v\n", err)
+			return
+		}
+
+		fmt.Print("Enter an optional description for the share file: ")
+		description, _ := reader.ReadString('\n')
+
+		// Palette images keep their colors by sharing palette indices
+		var allShares [][]Point
+		var width, height, depth int
+		var palette color.Palette
+		if isPaletted {
+			allShares = sss.sharePixels(palettedIndices(paletted))
+			width, height = paletted.Bounds().Dx(), paletted.Bounds().Dy()
+			palette = paletted.Palette
+		} else {
+			allShares, width, height, depth = sss.shareImageWithDepth(img)
+		}
 
-		err = saveImageShares(allShares, width, height, filename)
+		meta := ShareMetadata{
+			Threshold:   threshold,
+			XOffset:     *xOffset,
+			Palette:     palette,
+			Format:      format,
+			Scheme:      sss.Scheme(),
+			Prime:       sss.Prime(),
+			Description: strings.TrimSpace(description),
+		}
+		if depth == 16 {
+			meta.Depth = depth
+		}
+		if *shuffle {
+			if allShares, err = ShuffleShares(allShares); err != nil {
+				fmt.Printf("Error shuffling shares: %v\n", err)
+				return
+			}
+		}
+		err = saveImageSharesMeta(allShares, width, height, meta, filename)
 		if err != nil {
 			fmt.Printf("Error saving image shares: %v\n", err)
 			return
@@ -478,22 +1668,31 @@ func main() {
 		filename, _ := reader.ReadString('\n')
 		filename = strings.TrimSpace(filename)
 
-		allShares, width, height, err := loadImageShares(filename)
+		allShares, width, height, meta, err := loadImageSharesMeta(filename)
 		if err != nil {
 			fmt.Printf("Error loading image shares: %v\n", err)
 			return
 		}
+		if err := sss.applyShareMetadata(meta); err != nil {
+			fmt.Printf("Error loading image shares: %v\n", err)
+			return
+		}
 
-		fmt.Print("Enter output filename for reconstructed image (e.g., reconstructed.png): ")
+		if *ascii {
+			img, err := sss.reconstructImageMeta(allShares, width, height, meta)
+			if err != nil {
+				fmt.Printf("Error reconstructing image: %v\n", err)
+				return
+			}
+			fmt.Print(asciiArt(img, terminalWidth()))
+			return
+		}
+
+		fmt.Print("Enter output filename for reconstructed image (an extension such as .png picks the format; the original format is used otherwise): ")
 		outputPath, _ := reader.ReadString('\n')
 		outputPath = strings.TrimSpace(outputPath)
 
-		// Ensure the output path has a .png extension
-		if !strings.HasSuffix(strings.ToLower(outputPath), ".png") {
-			outputPath += ".png"
-		}
-
-		err = sss.ReconstructImage(allShares, width, height, outputPath)
+		outputPath, err = sss.ReconstructImageToFile(allShares, width, height, meta, outputPath)
 		if err != nil {
 			fmt.Printf("Error reconstructing image: %v\n", err)
 			return
@@ -501,6 +1700,57 @@ func main() {
 
 		fmt.Printf("Image reconstructed and saved to %s\n", outputPath)
 
+	case 5:
+		// Show share file info
+		fmt.Print("Enter share filename: ")
+		filename, _ := reader.ReadString('\n')
+		filename = strings.TrimSpace(filename)
+
+		meta, err := readShareHeader(filename)
+		if err != nil {
+			fmt.Printf("Error reading share file: %v\n", err)
+			return
+		}
+
+		printShareMetadata(os.Stdout, meta)
+
+	case 6:
+		// Run self-test
+		if err := runSelfTest(os.Stdout); err != nil {
+			fmt.Printf("Self-test failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("Self-test passed")
+
+	case 7:
+		// Reconstruct text shares and compare with the original
+		fmt.Print("Enter share filename: ")
+		filename, _ := reader.ReadString('\n')
+		filename = strings.TrimSpace(filename)
+
+		fmt.Print("Enter original filename: ")
+		originalPath, _ := reader.ReadString('\n')
+		originalPath = strings.TrimSpace(originalPath)
+
+		meta, err := readShareHeader(filename)
+		if err == nil {
+			err = sss.applyShareMetadata(meta)
+		}
+		if err != nil {
+			fmt.Printf("Error verifying shares: %v\n", err)
+			return
+		}
+		offset, err := sss.DiffTextSharesAgainst(filename, originalPath)
+		if err != nil {
+			fmt.Printf("Error verifying shares: %v\n", err)
+			return
+		}
+
+		printDiffResult(os.Stdout, offset)
+		if offset >= 0 {
+			os.Exit(1)
+		}
+
 	default:
 		fmt.Println("Invalid choice")
 	}
@@ -0,0 +1,1062 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math/big"
+	mrand "math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newTestSharing builds an instance or fails the test
+func newTestSharing(tb testing.TB, threshold, numShares int) *ShamirSecretSharing {
+	tb.Helper()
+	sss, err := NewShamirSecretSharing(threshold, numShares)
+	if err != nil {
+		tb.Fatalf("NewShamirSecretSharing(%d, %d): %v", threshold, numShares, err)
+	}
+	return sss
+}
+
+// writeTestFile writes content to a file in a fresh temporary directory
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeTestPNG encodes img as a PNG in a fresh temporary directory
+func writeTestPNG(t *testing.T, img image.Image) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "image.png")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestModInverse(t *testing.T) {
+	p := Prime31.Int64()
+	for _, tc := range []struct{ a, m, want int64 }{
+		{3, 11, 4},
+		{10, 17, 12},
+		{17, 3120, 2753}, // the textbook RSA example: e = 17, phi = 3120, d = 2753
+		{2, p, 1073741824},
+		{p - 2, p, 1073741823},
+		{123456789, p, 391219981},
+		{65521, p, 1675614749},
+		{-1, p, p - 1},
+	} {
+		got := modInverse(big.NewInt(tc.a), big.NewInt(tc.m))
+		if got.Int64() != tc.want {
+			t.Errorf("modInverse(%d, %d) = %s, want %d", tc.a, tc.m, got, tc.want)
+		}
+		if check := new(big.Int).Mul(got, big.NewInt(tc.a)); check.Mod(check, big.NewInt(tc.m)).Int64() != 1 {
+			t.Errorf("modInverse(%d, %d) = %s is not an inverse", tc.a, tc.m, got)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("modInverse(2, 4) did not panic")
+		}
+	}()
+	modInverse(big.NewInt(2), big.NewInt(4))
+}
+
+func TestEvaluatePolynomial(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	// f(x) = 6 + 2x + x^2
+	coefficients := []*big.Int{big.NewInt(6), big.NewInt(2), big.NewInt(1)}
+	for x, want := range map[int]int64{0: 6, 1: 9, 2: 14, 3: 21} {
+		if got := sss.evaluatePolynomial(coefficients, x); got.Int64() != want {
+			t.Errorf("f(%d) = %s, want %d", x, got, want)
+		}
+	}
+
+	// Values wrap around the prime: f(x) = (p-1) + x gives f(1) = 0
+	wrap := []*big.Int{new(big.Int).Sub(Prime31, big.NewInt(1)), big.NewInt(1)}
+	if got := sss.evaluatePolynomial(wrap, 1); got.Sign() != 0 {
+		t.Errorf("f(1) = %s, want 0 mod PRIME", got)
+	}
+}
+
+func TestLagrangeInterpolation(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	points := []Point{
+		{X: big.NewInt(1), Y: big.NewInt(9)},
+		{X: big.NewInt(2), Y: big.NewInt(14)},
+		{X: big.NewInt(3), Y: big.NewInt(21)},
+	}
+	if got := sss.lagrangeInterpolation(points); got.Int64() != 6 {
+		t.Errorf("f(0) = %s, want 6", got)
+	}
+	// Any three points on the same polynomial give the same f(0)
+	others := []Point{points[2], {X: big.NewInt(5), Y: big.NewInt(41)}, points[0]}
+	if got := sss.lagrangeInterpolation(others); got.Int64() != 6 {
+		t.Errorf("f(0) from x = 3, 5, 1: %s, want 6", got)
+	}
+}
+
+func TestFullQuorumDefaultsToPolynomial(t *testing.T) {
+	// 'A' shared 3-of-3 by f(x) = 65 + x + x^2 before the XOR scheme existed
+	path := writeTestFile(t, "a.txt", "1\n3\n1 67\n2 71\n3 77\n")
+	allShares, meta, err := loadTextSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sss := newTestSharing(t, 3, 3)
+	if err := sss.applyShareMetadata(meta); err != nil {
+		t.Fatal(err)
+	}
+	text, err := sss.ReconstructText(allShares)
+	if err != nil || text != "A" {
+		t.Fatalf("got %q, %v; want \"A\"", text, err)
+	}
+}
+
+func TestSetSchemeXORNeedsFullQuorum(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if err := sss.SetScheme(SchemeXOR); !errors.Is(err, ErrInvalidThreshold) {
+		t.Fatalf("SetScheme(SchemeXOR) on 2-of-3: got %v, want ErrInvalidThreshold", err)
+	}
+	if sss.Scheme() != SchemePolynomial {
+		t.Fatalf("scheme changed to %v after a rejected SetScheme", sss.Scheme())
+	}
+}
+
+func TestXORSchemeRecordedInHeader(t *testing.T) {
+	sss := newTestSharing(t, 3, 3)
+	if err := sss.SetScheme(SchemeXOR); err != nil {
+		t.Fatal(err)
+	}
+	allShares, err := sss.ShareText("xor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "xor.txt")
+	if err := saveTextSharesMeta(allShares, ShareMetadata{Scheme: sss.Scheme()}, path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, meta, err := loadTextSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Scheme != SchemeXOR {
+		t.Fatalf("header scheme = %v, want xor", meta.Scheme)
+	}
+
+	reader := newTestSharing(t, 3, 3)
+	if err := reader.applyShareMetadata(meta); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := reader.ReconstructText(loaded); err != nil || text != "xor" {
+		t.Fatalf("got %q, %v; want \"xor\"", text, err)
+	}
+}
+
+func TestXORSchemeRoundTrip(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5} {
+		sss := newTestSharing(t, n, n)
+		if err := sss.SetScheme(SchemeXOR); err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []int64{0, 1, 255, 2147483646} {
+			want := big.NewInt(secret)
+			if got := sss.ReconstructSecret(sss.GenerateShares(want)); got.Cmp(want) != 0 {
+				t.Errorf("%d-of-%d: reconstructed %s, want %s", n, n, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkGenerateSharesFullQuorum(b *testing.B) {
+	secret := big.NewInt(123456789)
+	for _, scheme := range []Scheme{SchemePolynomial, SchemeXOR} {
+		for _, n := range []int{2, 3, 5} {
+			b.Run(fmt.Sprintf("%s/n=%d", scheme, n), func(b *testing.B) {
+				sss := newTestSharing(b, n, n)
+				if err := sss.SetScheme(scheme); err != nil {
+					b.Fatal(err)
+				}
+				for b.Loop() {
+					sss.GenerateShares(secret)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkReconstructSecretFullQuorum(b *testing.B) {
+	secret := big.NewInt(123456789)
+	for _, scheme := range []Scheme{SchemePolynomial, SchemeXOR} {
+		for _, n := range []int{2, 3, 5} {
+			b.Run(fmt.Sprintf("%s/n=%d", scheme, n), func(b *testing.B) {
+				sss := newTestSharing(b, n, n)
+				if err := sss.SetScheme(scheme); err != nil {
+					b.Fatal(err)
+				}
+				shares := sss.GenerateShares(secret)
+				for b.Loop() {
+					sss.ReconstructSecret(shares)
+				}
+			})
+		}
+	}
+}
+
+func TestLoadSharesHugeCounts(t *testing.T) {
+	for name, content := range map[string]string{
+		"characters": "4611686018427387904\n2\n1 5\n2 9\n",
+		"shares":     "1\n4611686018427387904\n1 5\n",
+	} {
+		if _, err := loadTextShares(writeTestFile(t, "huge.txt", content)); !errors.Is(err, ErrTruncatedShares) {
+			t.Errorf("%s: got %v, want ErrTruncatedShares", name, err)
+		}
+	}
+
+	path := writeTestFile(t, "huge.img", "2147483648 2147483647 4611686016279904256\n2\n1 5\n2 9\n")
+	if _, _, _, _, err := loadImageSharesMeta(path); !errors.Is(err, ErrTruncatedShares) {
+		t.Errorf("image: got %v, want ErrTruncatedShares", err)
+	}
+}
+
+func TestLoadTextSharesPartial(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	text := "twenty characters!!!"
+	allShares, err := sss.ShareText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file promises 20 characters but ends inside the 11th
+	var content strings.Builder
+	fmt.Fprintf(&content, "%d\n", len(text))
+	for _, shares := range allShares[:10] {
+		fmt.Fprintf(&content, "%d\n", len(shares))
+		for _, p := range shares {
+			fmt.Fprintf(&content, "%s %s\n", p.X, p.Y)
+		}
+	}
+	fmt.Fprintf(&content, "3\n%s %s\n", allShares[10][0].X, allShares[10][0].Y)
+
+	prefix, complete, err := loadTextSharesPartial(writeTestFile(t, "truncated.txt", content.String()))
+	if complete != 10 || len(prefix) != 10 {
+		t.Fatalf("complete = %d with %d characters, want 10", complete, len(prefix))
+	}
+	if !errors.Is(err, ErrTruncatedShares) || !strings.Contains(err.Error(), "character 10 of 20") {
+		t.Fatalf("got %v, want ErrTruncatedShares naming character 10 of 20", err)
+	}
+	if got, err := sss.ReconstructText(prefix); err != nil || got != text[:10] {
+		t.Fatalf("prefix reconstructs to %q, %v; want %q", got, err, text[:10])
+	}
+}
+
+func TestShareTextConcurrentMatchesSequential(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	sss.SetConcurrency(4)
+	text := strings.Repeat("pooled big.Ints must not leak between shares ", 20)
+
+	concurrent, err := sss.ShareTextConcurrent(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sequential, err := sss.ShareText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, allShares := range map[string][][]Point{"concurrent": concurrent, "sequential": sequential} {
+		if got, err := sss.ReconstructText(allShares); err != nil || got != text {
+			t.Errorf("%s shares, ReconstructText: %v", name, err)
+		}
+		if got, err := sss.ReconstructTextConcurrent(allShares); err != nil || got != text {
+			t.Errorf("%s shares, ReconstructTextConcurrent: %v", name, err)
+		}
+	}
+
+	// Pooled temporaries are reused, so repeated evaluation must not drift
+	coefficients := []*big.Int{big.NewInt(6), big.NewInt(2), big.NewInt(1)}
+	for range 100 {
+		if got := sss.evaluatePolynomial(coefficients, 3); got.Int64() != 21 {
+			t.Fatalf("f(3) = %s after pool reuse, want 21", got)
+		}
+	}
+}
+
+func TestPutBigIntWipesWords(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	x := sss.getBigInt()
+	x.SetString("123456789012345678901234567890123456789012345678901234567890", 10)
+	// Shrinking keeps the larger value's words beyond the new length
+	x.Mod(x, big.NewInt(1000))
+	words := x.Bits()
+	words = words[:cap(words)]
+
+	sss.putBigInt(x)
+	for i, w := range words {
+		if w != 0 {
+			t.Fatalf("word %d of the pooled value is %#x after putBigInt, want 0", i, w)
+		}
+	}
+	if x.Sign() != 0 {
+		t.Fatalf("pooled value is %s, want 0", x)
+	}
+}
+
+func BenchmarkShareText(b *testing.B) {
+	text := strings.Repeat("x", 1024)
+	for name, share := range map[string]func(*ShamirSecretSharing, string) ([][]Point, error){
+		"sequential": (*ShamirSecretSharing).ShareText,
+		"concurrent": (*ShamirSecretSharing).ShareTextConcurrent,
+	} {
+		b.Run(name, func(b *testing.B) {
+			sss := newTestSharing(b, 3, 5)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := share(sss, text); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestNewtonMatchesLagrange(t *testing.T) {
+	for _, prime := range []*big.Int{Prime31, Prime127} {
+		sss := newTestSharing(t, 4, 7)
+		if err := sss.SetPrime(prime); err != nil {
+			t.Fatal(err)
+		}
+		for range 50 {
+			secret, err := rand.Int(rand.Reader, prime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			shares := sss.GenerateShares(secret)
+			// A random subset in random order
+			mrand.Shuffle(len(shares), func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+			subset := shares[:4]
+
+			lagrange := sss.lagrangeInterpolation(subset)
+			newton := sss.newtonInterpolation(subset)
+			if lagrange.Cmp(newton) != 0 || lagrange.Cmp(secret) != 0 {
+				t.Fatalf("%d-bit prime: Lagrange %s, Newton %s, secret %s", prime.BitLen(), lagrange, newton, secret)
+			}
+		}
+	}
+
+	sss := newTestSharing(t, 3, 5)
+	sss.SetInterpolation(InterpolationNewton)
+	if got, err := sss.ReconstructText(mustShareText(t, sss, "newton")); err != nil || got != "newton" {
+		t.Fatalf("Newton reconstruction: got %q, %v", got, err)
+	}
+}
+
+// Run with -race to check for data races
+func TestSharedInstanceConcurrentUse(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for g := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 20 {
+				secret := big.NewInt(int64(g*1000 + i))
+				shares := sss.GenerateShares(secret)
+				if got := sss.ReconstructSecret(shares[1:4]); got.Cmp(secret) != 0 {
+					errs <- fmt.Errorf("goroutine %d: reconstructed %s, want %s", g, got, secret)
+					return
+				}
+				text := fmt.Sprintf("goroutine %d", g)
+				allShares, err := sss.ShareText(text)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got, err := sss.ReconstructText(allShares); err != nil || got != text {
+					errs <- fmt.Errorf("goroutine %d: text %q, %v", g, got, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestReconstructImageBytes(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 3))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 20)
+	}
+	sss := newTestSharing(t, 2, 3)
+	allShares, width, height, err := sss.ShareImage(writeTestPNG(t, img))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pixels, err := sss.ReconstructImageBytes(allShares, width, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pixels, img.Pix) {
+		t.Fatalf("got %v, want %v", pixels, img.Pix)
+	}
+	if _, err := sss.ReconstructImageBytes(allShares, 3, 3); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("wrong dimensions: got %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func TestXOffsetKeepsDealersApart(t *testing.T) {
+	first := newTestSharing(t, 2, 3)
+	second := newTestSharing(t, 2, 3)
+	second.SetXOffset(3)
+	secret := big.NewInt(2024)
+
+	a, b := first.GenerateShares(secret), second.GenerateShares(secret)
+	for i, share := range b {
+		if want := int64(3 + i + 1); share.X.Int64() != want {
+			t.Fatalf("share %d has x = %s, want %d", i, share.X, want)
+		}
+		for _, other := range a {
+			if share.X.Cmp(other.X) == 0 {
+				t.Fatalf("dealers share x = %s", share.X)
+			}
+		}
+	}
+	if got := first.ReconstructSecret(b[1:]); got.Cmp(secret) != 0 {
+		t.Fatalf("offset shares reconstruct to %s, want %s", got, secret)
+	}
+
+	// The offset must leave every x below the prime
+	if err := second.SetPrime(big.NewInt(5)); !errors.Is(err, ErrTooManyShares) {
+		t.Fatalf("offset 3 with 3 shares over GF(5): got %v, want ErrTooManyShares", err)
+	}
+
+	path := saveTestTextShares(t, second, "offset", ShareMetadata{XOffset: 3}, t.TempDir(), "offset.txt")
+	allShares, meta, err := loadTextSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.XOffset != 3 || allShares[0][0].X.Int64() != 4 {
+		t.Fatalf("header offset %d, first x %s; want 3 and 4", meta.XOffset, allShares[0][0].X)
+	}
+	if text, err := first.ReconstructText(allShares); err != nil || text != "offset" {
+		t.Fatalf("got %q, %v; want \"offset\"", text, err)
+	}
+}
+
+func TestReconstructSecretVerboseReportsSubset(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	secret := big.NewInt(777)
+	shares := sss.GenerateShares(secret)
+
+	input := []Point{shares[4], shares[1], shares[1], shares[3], shares[0]}
+	got, used, err := sss.ReconstructSecretVerbose(input)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("got %v, %v; want %s", got, err, secret)
+	}
+	// The duplicate is dropped and the leading distinct shares are used
+	want := []Point{shares[4], shares[1], shares[3]}
+	if len(used) != len(want) {
+		t.Fatalf("used %d shares, want %d", len(used), len(want))
+	}
+	for i := range want {
+		if used[i].X.Cmp(want[i].X) != 0 || used[i].Y.Cmp(want[i].Y) != 0 {
+			t.Fatalf("used[%d] = %v, want %v", i, used[i], want[i])
+		}
+	}
+
+	if _, _, err := sss.ReconstructSecretVerbose(shares[:2]); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("two shares: got %v, want ErrInsufficientShares", err)
+	}
+	if _, _, err := sss.ReconstructSecretVerbose([]Point{shares[0], shares[0], shares[0]}); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("one share three times: got %v, want ErrInsufficientShares", err)
+	}
+}
+
+func TestMaxImagePixelsGuard(t *testing.T) {
+	old := MaxImagePixels
+	t.Cleanup(func() { MaxImagePixels = old })
+	MaxImagePixels = 100
+
+	sss := newTestSharing(t, 2, 3)
+	if _, _, _, err := sss.ShareImage(writeTestPNG(t, image.NewGray(image.Rect(0, 0, 10, 11)))); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("110 pixels: got %v, want ErrImageTooLarge", err)
+	}
+	if _, w, h, err := sss.ShareImage(writeTestPNG(t, image.NewGray(image.Rect(0, 0, 10, 10)))); err != nil || w != 10 || h != 10 {
+		t.Fatalf("100 pixels: got %dx%d, %v", w, h, err)
+	}
+
+	// A header claiming 100000x100000 with no pixel data behind it must be
+	// rejected from the header alone
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	bomb := buf.Bytes()[:33] // signature and IHDR chunk
+	binary.BigEndian.PutUint32(bomb[16:], 100000)
+	binary.BigEndian.PutUint32(bomb[20:], 100000)
+	binary.BigEndian.PutUint32(bomb[29:], crc32.ChecksumIEEE(bomb[12:29]))
+	MaxImagePixels = old
+	if _, _, _, err := sss.ShareImageReader(bytes.NewReader(bomb)); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("decompression bomb header: got %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestImageReaderWriterRoundTrip(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 6, 4))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 10)
+	}
+	var in bytes.Buffer
+	if err := png.Encode(&in, src); err != nil {
+		t.Fatal(err)
+	}
+
+	sss := newTestSharing(t, 2, 3)
+	allShares, width, height, err := sss.ShareImageReader(&in)
+	if err != nil || width != 6 || height != 4 {
+		t.Fatalf("ShareImageReader = %dx%d, %v", width, height, err)
+	}
+	img, err := sss.ReconstructImage(allShares, width, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := ReconstructImageWriter(img, &out); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gray, ok := decoded.(*image.Gray)
+	if !ok || !bytes.Equal(gray.Pix, src.Pix) {
+		t.Fatalf("round trip changed the pixels: %T", decoded)
+	}
+
+	if _, _, _, err := sss.ShareImageReader(strings.NewReader("not an image")); !errors.Is(err, image.ErrFormat) {
+		t.Fatalf("garbage input: got %v, want image.ErrFormat", err)
+	}
+}
+
+func TestMinSharesAvailable(t *testing.T) {
+	if got := MinSharesAvailable(nil); got != 0 {
+		t.Fatalf("no secrets: got %d, want 0", got)
+	}
+
+	sss := newTestSharing(t, 3, 5)
+	allShares := mustShareText(t, sss, "partial")
+	if got := MinSharesAvailable(allShares); got != 5 {
+		t.Fatalf("complete shares: got %d, want 5", got)
+	}
+
+	// One character lost shares from a partial participant file
+	allShares[3] = allShares[3][:2]
+	path := filepath.Join(t.TempDir(), "partial.txt")
+	if err := saveTextShares(allShares, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadTextShares(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := MinSharesAvailable(loaded); got != 2 {
+		t.Fatalf("loaded file: got %d, want 2", got)
+	}
+	_, err = sss.ReconstructText(loaded)
+	if !errors.Is(err, ErrInsufficientShares) || !strings.Contains(err.Error(), "only 2 shares, need 3") {
+		t.Fatalf("got %v, want ErrInsufficientShares reporting 2 of 3", err)
+	}
+}
+
+func TestNumSharesBelowPrime(t *testing.T) {
+	limit := int(PRIME.Int64())
+	if _, err := NewShamirSecretSharing(2, limit); !errors.Is(err, ErrTooManyShares) {
+		t.Fatalf("numShares = PRIME: got %v, want ErrTooManyShares", err)
+	}
+	if _, err := NewShamirSecretSharing(2, limit-1); err != nil {
+		t.Fatalf("numShares = PRIME-1: %v", err)
+	}
+
+	// A small field brings the limit within reach
+	sss := newTestSharing(t, 2, 6)
+	if err := sss.SetPrime(big.NewInt(7)); err != nil {
+		t.Fatalf("6 shares over GF(7): %v", err)
+	}
+	sss = newTestSharing(t, 2, 7)
+	if err := sss.SetPrime(big.NewInt(7)); !errors.Is(err, ErrTooManyShares) {
+		t.Fatalf("7 shares over GF(7): got %v, want ErrTooManyShares", err)
+	}
+}
+
+func TestLoadImageSharesTruncated(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	path := filepath.Join(t.TempDir(), "image.txt")
+	if err := saveImageShares(sss.sharePixels([]uint8{1, 2, 3, 4}), 2, 2, path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	dims := slices.IndexFunc(lines, func(line string) bool { return line == "2 2 4\n" })
+	if dims < 0 {
+		t.Fatalf("no dimension line in %q", data)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		content string
+		read    string
+	}{
+		{"after the dimension line", strings.Join(lines[:dims+1], ""), "read 0 of 4 pixels"},
+		{"after a share count", strings.Join(lines[:dims+6], ""), "read 1 of 4 pixels"},
+		{"inside a share line", strings.Join(lines[:dims+7], "") + lines[dims+7][:2], "read 1 of 4 pixels"},
+	} {
+		_, _, _, err := loadImageShares(writeTestFile(t, "truncated.txt", tc.content))
+		if !errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, ErrTruncatedShares) || !strings.Contains(err.Error(), tc.read) {
+			t.Errorf("%s: got %v, want io.ErrUnexpectedEOF after %s", tc.name, err, tc.read)
+		}
+	}
+}
+
+func TestThresholdOfThreeShares(t *testing.T) {
+	sss := newTestSharing(t, 3, 3)
+	if sss.IsRedundant() {
+		t.Fatal("3-of-3 reported as redundant")
+	}
+	if !newTestSharing(t, 3, 4).IsRedundant() {
+		t.Fatal("3-of-4 reported as not redundant")
+	}
+
+	secret := big.NewInt(3333)
+	shares := sss.GenerateShares(secret)
+	if len(shares) != 3 {
+		t.Fatalf("got %d shares, want 3", len(shares))
+	}
+	if got := sss.ReconstructSecret([]Point{shares[2], shares[0], shares[1]}); got.Cmp(secret) != 0 {
+		t.Fatalf("reconstructed %s, want %s", got, secret)
+	}
+	if text, err := sss.ReconstructText(mustShareText(t, sss, "all")); err != nil || text != "all" {
+		t.Fatalf("text: got %q, %v", text, err)
+	}
+	if _, _, err := sss.ReconstructSecretVerbose(shares[:2]); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("losing one share: got %v, want ErrInsufficientShares", err)
+	}
+}
+
+func TestShareIndexAbovePrimeRejected(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	shares := sss.GenerateShares(big.NewInt(65))
+	// x = PRIME+1 reduces to x = 1 and would give a zero Lagrange denominator
+	aliased := Point{X: new(big.Int).Add(PRIME, big.NewInt(1)), Y: shares[1].Y}
+
+	for name, points := range map[string][]Point{
+		"PRIME+1":  {shares[0], aliased},
+		"PRIME":    {{X: new(big.Int).Set(PRIME), Y: big.NewInt(1)}, shares[1]},
+		"zero":     {{X: big.NewInt(0), Y: big.NewInt(1)}, shares[1]},
+		"negative": {{X: big.NewInt(-1), Y: big.NewInt(1)}, shares[1]},
+	} {
+		if _, _, err := sss.ReconstructSecretVerbose(points); !errors.Is(err, ErrInvalidShareIndex) {
+			t.Errorf("%s: got %v, want ErrInvalidShareIndex", name, err)
+		}
+		if _, err := sss.ReconstructText([][]Point{points}); !errors.Is(err, ErrInvalidShareIndex) {
+			t.Errorf("%s, ReconstructText: got %v, want ErrInvalidShareIndex", name, err)
+		}
+	}
+}
+
+func TestPointIsValid(t *testing.T) {
+	prime := big.NewInt(7)
+	for _, tc := range []struct {
+		name string
+		p    Point
+		want bool
+	}{
+		{"valid", Point{X: big.NewInt(1), Y: big.NewInt(6)}, true},
+		{"zero y", Point{X: big.NewInt(3), Y: big.NewInt(0)}, true},
+		{"nil x", Point{Y: big.NewInt(1)}, false},
+		{"nil y", Point{X: big.NewInt(1)}, false},
+		{"zero x", Point{X: big.NewInt(0), Y: big.NewInt(1)}, false},
+		{"negative x", Point{X: big.NewInt(-2), Y: big.NewInt(1)}, false},
+		{"negative y", Point{X: big.NewInt(1), Y: big.NewInt(-1)}, false},
+		{"y = prime", Point{X: big.NewInt(1), Y: big.NewInt(7)}, false},
+		{"y > prime", Point{X: big.NewInt(1), Y: big.NewInt(100)}, false},
+	} {
+		if got := tc.p.IsValid(prime); got != tc.want {
+			t.Errorf("%s: IsValid = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLoadTextSharesInvalidValues(t *testing.T) {
+	for name, content := range map[string]string{
+		"bad x": "1\n2\nx 5\n2 9\n",
+		"bad y": "1\n2\n1 5\n2 nine\n",
+		"hex y": "1\n2\n1 5\n2 0x9\n",
+	} {
+		if _, err := loadTextShares(writeTestFile(t, "bad.txt", content)); err == nil {
+			t.Errorf("%s: loaded without error", name)
+		}
+	}
+
+	// A y outside the field loads but is rejected before interpolation
+	allShares, err := loadTextShares(writeTestFile(t, "big.txt", "1\n2\n1 5\n2 "+PRIME.String()+"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTestSharing(t, 2, 2).ReconstructText(allShares); !errors.Is(err, ErrInvalidShare) {
+		t.Fatalf("y = PRIME: got %v, want ErrInvalidShare", err)
+	}
+}
+
+func TestLoadSharesCRLF(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	dir := t.TempDir()
+	textPath := saveTestTextShares(t, sss, "windows", ShareMetadata{Description: "saved on Windows"}, dir, "text.txt")
+	imagePath := filepath.Join(dir, "image.txt")
+	if err := saveImageShares(sss.sharePixels([]uint8{10, 20, 30, 40}), 2, 2, imagePath); err != nil {
+		t.Fatal(err)
+	}
+	toCRLF := func(path string) {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n")), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	toCRLF(textPath)
+	toCRLF(imagePath)
+
+	allShares, meta, err := loadTextSharesMeta(textPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Description != "saved on Windows" {
+		t.Errorf("description = %q", meta.Description)
+	}
+	if text, err := sss.ReconstructText(allShares); err != nil || text != "windows" {
+		t.Fatalf("text: got %q, %v", text, err)
+	}
+
+	imageShares, width, height, err := loadImageShares(imagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pixels, err := sss.ReconstructImageBytes(imageShares, width, height)
+	if err != nil || !bytes.Equal(pixels, []byte{10, 20, 30, 40}) {
+		t.Fatalf("image: got %v, %v", pixels, err)
+	}
+}
+
+func TestShareImageFromBytesReader(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i := range 6 {
+		src.Set(i%3, i/3, color.RGBA{R: uint8(i * 40), G: uint8(255 - i*30), B: uint8(i * 9), A: 255})
+	}
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, src); err != nil {
+		t.Fatal(err)
+	}
+
+	sss := newTestSharing(t, 2, 3)
+	allShares, width, height, err := sss.ShareImageReader(bytes.NewReader(encoded.Bytes()))
+	if err != nil || width != 3 || height != 2 {
+		t.Fatalf("ShareImageReader = %dx%d, %v", width, height, err)
+	}
+	pixels, err := sss.ReconstructImageBytes(allShares, width, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, got := range pixels {
+		if want := GrayModelConversion(src.At(i%3, i/3)); got != want {
+			t.Errorf("pixel %d = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestShareImageWrapsReader(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 4, 3))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 20)
+	}
+	sss := newTestSharing(t, 2, 3)
+	allShares, width, height, err := sss.ShareImage(writeTestPNG(t, src))
+	if err != nil || width != 4 || height != 3 {
+		t.Fatalf("ShareImage = %dx%d, %v", width, height, err)
+	}
+	pixels, err := sss.ReconstructImageBytes(allShares, width, height)
+	if err != nil || !bytes.Equal(pixels, src.Pix) {
+		t.Fatalf("got %v, %v; want %v", pixels, err, src.Pix)
+	}
+
+	if _, _, _, err := sss.ShareImage(filepath.Join(t.TempDir(), "missing.png")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("missing file: got %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestLoadTextSharesMismatchedCounts(t *testing.T) {
+	// Secret 1 declares fewer shares than the header threshold
+	content := "#threshold 3\n" +
+		"3\n" +
+		"3\n1 10\n2 20\n3 30\n" +
+		"2\n1 11\n2 21\n" +
+		"3\n1 12\n2 22\n3 32\n"
+	_, err := loadTextShares(writeTestFile(t, "mismatched.txt", content))
+	if !errors.Is(err, ErrInsufficientShares) || !strings.Contains(err.Error(), "secret 1 has 2 shares") {
+		t.Fatalf("got %v, want ErrInsufficientShares naming secret 1", err)
+	}
+
+	// Uneven counts all at or above the threshold load; reconstruction uses
+	// the first threshold shares of each
+	sss := newTestSharing(t, 2, 3)
+	allShares := mustShareText(t, sss, "ok")
+	allShares[1] = allShares[1][:2]
+	path := filepath.Join(t.TempDir(), "uneven.txt")
+	if err := saveTextSharesMeta(allShares, ShareMetadata{Threshold: 2}, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadTextShares(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := sss.ReconstructText(loaded); err != nil || text != "ok" {
+		t.Fatalf("got %q, %v; want \"ok\"", text, err)
+	}
+}
+
+func TestImagePixelCountMismatch(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	// Five pixels of shares for a 2x2 image, as after a manual edit
+	allShares := sss.sharePixels([]uint8{1, 2, 3, 4, 5})
+
+	var content strings.Builder
+	content.WriteString("2 2 5\n")
+	for _, shares := range allShares {
+		fmt.Fprintf(&content, "%d\n", len(shares))
+		for _, p := range shares {
+			fmt.Fprintf(&content, "%s %s\n", p.X, p.Y)
+		}
+	}
+	if _, _, _, err := loadImageShares(writeTestFile(t, "extra.txt", content.String())); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("5 pixels declared for 2x2: got %v, want ErrDimensionMismatch", err)
+	}
+
+	if _, err := sss.ReconstructImage(allShares, 2, 2); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("ReconstructImage with a leftover pixel: got %v, want ErrDimensionMismatch", err)
+	}
+	if _, err := sss.ReconstructImageParallel(allShares, 2, 2, 0); !errors.Is(err, ErrDimensionMismatch) {
+		t.Fatalf("ReconstructImageParallel with a leftover pixel: got %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func TestIsGrayscale(t *testing.T) {
+	colored := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	gray := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := range 8 {
+		for x := range 8 {
+			v := uint8(x*30 + y)
+			gray.Set(x, y, color.RGBA{v, v, v, 255})
+			colored.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	colored.Set(0, 0, color.RGBA{200, 10, 10, 255})
+
+	tests := []struct {
+		name string
+		img  image.Image
+		want bool
+	}{
+		{"gray type", image.NewGray(image.Rect(0, 0, 4, 4)), true},
+		{"gray16 type", image.NewGray16(image.Rect(0, 0, 4, 4)), true},
+		{"rgba with equal channels", gray, true},
+		{"rgba with one red pixel", colored, false},
+	}
+	for _, tt := range tests {
+		if got := isGrayscale(tt.img); got != tt.want {
+			t.Errorf("%s: isGrayscale = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDedupeShares(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	secret := big.NewInt(99991)
+	shares := sss.GenerateShares(secret)
+
+	// Participant 1 appears twice, as after merging overlapping share files
+	merged := []Point{shares[0], shares[0], shares[3], shares[4]}
+	deduped, err := DedupeShares(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deduped) != 3 || deduped[0].X.Cmp(shares[0].X) != 0 || deduped[1].X.Cmp(shares[3].X) != 0 {
+		t.Fatalf("deduped to %v, want shares 1, 4 and 5", deduped)
+	}
+	if got := sss.ReconstructSecret(deduped); got.Cmp(secret) != 0 {
+		t.Fatalf("deduped shares reconstruct to %s, want %s", got, secret)
+	}
+	if len(merged) != 4 || merged[1].X.Cmp(shares[0].X) != 0 {
+		t.Fatal("DedupeShares modified its input")
+	}
+
+	// Without dedup the repeat would be among the first three shares used
+	allShares := mustShareText(t, sss, "dup")
+	for i := range allShares {
+		allShares[i] = append([]Point{allShares[i][1]}, allShares[i]...)
+	}
+	if text, err := sss.ReconstructText(allShares); err != nil || text != "dup" {
+		t.Fatalf("text with a duplicated participant: got %q, %v; want \"dup\"", text, err)
+	}
+
+	conflict := []Point{shares[0], {X: shares[0].X, Y: new(big.Int).Add(shares[0].Y, big.NewInt(1))}, shares[1]}
+	if _, err := DedupeShares(conflict); !errors.Is(err, ErrInconsistentShares) {
+		t.Fatalf("same x with different y: got %v, want ErrInconsistentShares", err)
+	}
+	allShares[0][0] = conflict[1]
+	if _, err := sss.ReconstructText(allShares); !errors.Is(err, ErrInconsistentShares) {
+		t.Fatalf("text with conflicting shares: got %v, want ErrInconsistentShares", err)
+	}
+}
+
+func TestPointFormatting(t *testing.T) {
+	p := Point{X: big.NewInt(3), Y: big.NewInt(255)}
+	tests := []struct {
+		format string
+		arg    any
+		want   string
+	}{
+		{"%v", p, "Point(x=3, y=255)"},
+		{"%s", p, "Point(x=3, y=255)"},
+		{"%q", p, `"Point(x=3, y=255)"`},
+		{"%x", p, "Point(x=3, y=ff)"},
+		{"%#v", p, "main.Point{X: big.NewInt(3), Y: big.NewInt(255)}"},
+		{"%d", p, "%!d(Point(x=3, y=255))"},
+		{"%v", []Point{p, {X: big.NewInt(4), Y: big.NewInt(0)}}, "[Point(x=3, y=255) Point(x=4, y=0)]"},
+		{"%v", Point{X: big.NewInt(1)}, "Point(x=1, y=<nil>)"},
+		{"%v", NewChecksummedPoint(p), fmt.Sprintf("Point(x=3, y=255) crc=%08x", shareCRC(p))},
+	}
+	for _, tt := range tests {
+		got := fmt.Sprintf(tt.format, tt.arg)
+		if got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+		if strings.Contains(got, "0xc") {
+			t.Errorf("Sprintf(%q) = %q prints a pointer", tt.format, got)
+		}
+	}
+}
+
+func TestLoadTextSharesTruncated(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	path := filepath.Join(t.TempDir(), "text.txt")
+	if err := saveTextShares(mustShareText(t, sss, "ab"), path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	count := slices.Index(lines, "2\n")
+	if count < 0 {
+		t.Fatalf("no character count line in %q", data)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		content string
+		read    string
+	}{
+		{"after the character count", strings.Join(lines[:count+1], ""), "character 0 of 2"},
+		{"after a share count", strings.Join(lines[:count+6], ""), "character 1 of 2"},
+		{"inside a share line", strings.Join(lines[:count+7], "") + lines[count+7][:2], "character 1 of 2"},
+	} {
+		allShares, err := loadTextShares(writeTestFile(t, "truncated.txt", tc.content))
+		if allShares != nil || !errors.Is(err, ErrTruncatedShares) || !strings.Contains(err.Error(), tc.read) {
+			t.Errorf("%s: got %d characters and %v, want ErrTruncatedShares at %s", tc.name, len(allShares), err, tc.read)
+		}
+	}
+
+	// A line too long for the scanner is a read error, not a short file
+	long := "1\n2\n1 " + strings.Repeat("9", bufio.MaxScanTokenSize) + "\n2 5\n"
+	_, err = loadTextShares(writeTestFile(t, "long.txt", long))
+	if !errors.Is(err, bufio.ErrTooLong) || !strings.Contains(err.Error(), "reading share file at character 0") {
+		t.Fatalf("overlong line: got %v, want bufio.ErrTooLong at character 0", err)
+	}
+}
+
+func TestSetConversion(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	colors := []color.RGBA{{200, 10, 10, 255}, {10, 200, 10, 255}, {90, 90, 250, 255}}
+	for x, c := range colors {
+		src.SetRGBA(x, 0, c)
+	}
+	path := writeTestPNG(t, src)
+	sss := newTestSharing(t, 2, 3)
+
+	shared := func() []uint8 {
+		t.Helper()
+		allShares, width, height, err := sss.ShareImage(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pixels, err := sss.ReconstructImageBytes(allShares, width, height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pixels
+	}
+
+	sss.SetConversion(func(c color.Color) uint8 {
+		r, _, _, _ := c.RGBA()
+		return uint8(r >> 8)
+	})
+	if got := shared(); !bytes.Equal(got, []uint8{200, 10, 90}) {
+		t.Fatalf("red channel: shared %v, want [200 10 90]", got)
+	}
+
+	// nil restores the luminance-weighted default
+	sss.SetConversion(nil)
+	want := make([]uint8, len(colors))
+	for i, c := range colors {
+		want[i] = GrayModelConversion(c)
+	}
+	if got := shared(); !bytes.Equal(got, want) {
+		t.Fatalf("default: shared %v, want %v", got, want)
+	}
+}
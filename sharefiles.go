@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MergeTextShareFiles concatenates text share files that hold consecutive
+// parts of one message into a single share file. All files must use the same
+// number of shares per character and field and, when recorded, the same
+// threshold.
+func MergeTextShareFiles(files []string, out string) error {
+	if len(files) == 0 {
+		return errors.New("no share files to merge")
+	}
+
+	var merged [][]Point
+	var prime *big.Int
+	numShares, threshold := -1, 0
+
+	for _, filename := range files {
+		allShares, meta, err := loadTextSharesMeta(filename)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filename, err)
+		}
+
+		for i, shares := range allShares {
+			if numShares < 0 {
+				numShares = len(shares)
+			}
+			if len(shares) != numShares {
+				return fmt.Errorf("%s: character %d has %d shares, expected %d", filename, i, len(shares), numShares)
+			}
+		}
+
+		if meta.Threshold != 0 {
+			if threshold != 0 && meta.Threshold != threshold {
+				return fmt.Errorf("%s: threshold %d does not match %d", filename, meta.Threshold, threshold)
+			}
+			threshold = meta.Threshold
+		}
+		if prime != nil && sharePrime(meta).Cmp(prime) != 0 {
+			return fmt.Errorf("%s: shares use prime %s, not %s", filename, sharePrime(meta), prime)
+		}
+		prime = sharePrime(meta)
+
+		merged = append(merged, allShares...)
+	}
+
+	return saveTextSharesMeta(merged, ShareMetadata{Threshold: threshold, Prime: prime}, out)
+}
+
+// SplitShareFile splits a combined text share file into one file per
+// holder, each with that holder's single share for every character. The
+// files are written to outputDir as <input name>_share_<n>.txt, keep the
+// input's header except for the message digest (see holderMetadata), and
+// are returned in holder order.
+func SplitShareFile(inputPath, outputDir string) ([]string, error) {
+	allShares, meta, err := loadTextSharesMeta(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", inputPath, err)
+	}
+	byHolder, err := Transpose(allShares)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", inputPath, err)
+	}
+
+	meta = holderMetadata(meta)
+	base := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+	paths := make([]string, len(byHolder))
+	for h, shares := range byHolder {
+		// One share per character, in the usual character-major layout
+		column := make([][]Point, len(shares))
+		for i := range shares {
+			column[i] = shares[i : i+1]
+		}
+
+		paths[h] = filepath.Join(outputDir, fmt.Sprintf("%s_share_%d.txt", base, h+1))
+		if err := saveTextSharesMeta(column, meta, paths[h]); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// MergeShareFiles reverses SplitShareFile, combining holder files into one
+// share file with each character's shares in the order of paths. The header
+// of the first file is kept, so merging every split file in order
+// reproduces the original file exactly, less any #digest line, which holder
+// files do not carry.
+func MergeShareFiles(paths []string, outputPath string) error {
+	if len(paths) == 0 {
+		return errors.New("no share files to merge")
+	}
+
+	var merged [][]Point
+	var meta ShareMetadata
+	for n, path := range paths {
+		// A single holder's file is below the threshold by design, so it is
+		// read without loadTextSharesMeta's share count check
+		column, fileMeta, err := readTextShareFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if n == 0 {
+			merged = make([][]Point, len(column))
+			meta = fileMeta
+		} else if len(column) != len(merged) {
+			return fmt.Errorf("%s: has %d characters, expected %d", path, len(column), len(merged))
+		}
+		for i, shares := range column {
+			merged[i] = append(merged[i], shares...)
+		}
+	}
+
+	return saveTextSharesMeta(merged, meta, outputPath)
+}
+
+// readTextShareFile reads a text share file without checking share counts
+func readTextShareFile(filename string) ([][]Point, ShareMetadata, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, ShareMetadata{}, err
+	}
+	defer file.Close()
+
+	allShares, meta, _, err := readTextShares(file)
+	return allShares, meta, err
+}
+
+// AppendTextShares adds shares for more characters to the end of an
+// existing text share file. Only the header is parsed: the character count
+// is rewritten and the existing shares are copied across unchanged, so the
+// cost does not depend on re-encoding them. A #digest line is dropped, as
+// it no longer matches the longer message. The file is replaced atomically,
+// so a failure leaves the original intact.
+func AppendTextShares(filename string, newShares [][]Point) (err error) {
+	src, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	reader := bufio.NewReader(src)
+	writer := bufio.NewWriter(tmp)
+	threshold, count := 0, -1
+	for count < 0 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("%w: missing character count", ErrTruncatedShares)
+		}
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "#") {
+			if count, err = strconv.Atoi(trimmed); err != nil || count < 0 {
+				return fmt.Errorf("invalid character count %q", trimmed)
+			}
+			break
+		}
+
+		key, value, _ := strings.Cut(trimmed[1:], " ")
+		switch key {
+		case "digest":
+			continue
+		case "threshold":
+			threshold, _ = strconv.Atoi(value)
+		}
+		writer.WriteString(line)
+	}
+	if err := checkShareCounts(newShares, threshold); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(writer, "%d\n", count+len(newShares))
+	copied, err := io.Copy(writer, reader)
+	if err != nil {
+		return err
+	}
+	// Start the new shares on a line of their own even if the file lacks a final newline
+	var last [1]byte
+	if copied > 0 {
+		if _, err := src.ReadAt(last[:], info.Size()-1); err != nil {
+			return err
+		}
+		if last[0] != '\n' {
+			writer.WriteByte('\n')
+		}
+	}
+	writeShareBlocks(writer, newShares)
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// saveTestTextShares shares text and saves it with meta to dir/name
+func saveTestTextShares(t *testing.T, sss *ShamirSecretSharing, text string, meta ShareMetadata, dir, name string) string {
+	t.Helper()
+	allShares, err := sss.ShareText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := saveTextSharesMeta(allShares, meta, path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSplitAndMergeShareFile(t *testing.T) {
+	dir := t.TempDir()
+	sss := newTestSharing(t, 2, 3)
+	meta := ShareMetadata{Threshold: 2, Description: "split me", Created: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	combined := saveTestTextShares(t, sss, "secret", meta, dir, "combined.txt")
+
+	paths, err := SplitShareFile(combined, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("got %d holder files, want 3", len(paths))
+	}
+	for _, path := range paths {
+		column, _, err := readTextShareFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(column) != len("secret") {
+			t.Errorf("%s: %d characters, want %d", path, len(column), len("secret"))
+		}
+		for i, shares := range column {
+			if len(shares) != 1 {
+				t.Errorf("%s: character %d has %d shares, want 1", path, i, len(shares))
+			}
+		}
+	}
+
+	merged := filepath.Join(dir, "merged.txt")
+	if err := MergeShareFiles(paths, merged); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := os.ReadFile(combined)
+	got, _ := os.ReadFile(merged)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("merged file differs from the original:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSplitShareFileDropsDigest(t *testing.T) {
+	dir := t.TempDir()
+	sss := newTestSharing(t, 2, 3)
+	meta := ShareMetadata{Threshold: 2, Digest: MessageDigest([]byte("1234"))}
+	combined := saveTestTextShares(t, sss, "1234", meta, dir, "pin.txt")
+
+	paths, err := SplitShareFile(combined, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), "#digest") {
+			t.Errorf("%s carries the message digest", path)
+		}
+	}
+
+	merged := filepath.Join(dir, "merged.txt")
+	if err := MergeShareFiles(paths[1:], merged); err != nil {
+		t.Fatal(err)
+	}
+	allShares, err := loadTextShares(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := sss.ReconstructText(allShares); err != nil || text != "1234" {
+		t.Fatalf("got %q, %v; want \"1234\"", text, err)
+	}
+}
+
+func TestMergeTextShareFiles(t *testing.T) {
+	dir := t.TempDir()
+	sss := newTestSharing(t, 2, 3)
+	first := saveTestTextShares(t, sss, "first half, ", ShareMetadata{Threshold: 2}, dir, "a.txt")
+	second := saveTestTextShares(t, sss, "second half", ShareMetadata{Threshold: 2}, dir, "b.txt")
+
+	merged := filepath.Join(dir, "merged.txt")
+	if err := MergeTextShareFiles([]string{first, second}, merged); err != nil {
+		t.Fatal(err)
+	}
+	allShares, err := loadTextShares(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := sss.ReconstructText(allShares); err != nil || text != "first half, second half" {
+		t.Fatalf("got %q, %v", text, err)
+	}
+
+	other := saveTestTextShares(t, newTestSharing(t, 3, 3), "x", ShareMetadata{Threshold: 3}, dir, "c.txt")
+	if err := MergeTextShareFiles([]string{first, other}, merged); err == nil {
+		t.Fatal("merged files with different thresholds")
+	}
+}
+
+func TestAppendTextShares(t *testing.T) {
+	dir := t.TempDir()
+	sss := newTestSharing(t, 2, 3)
+	meta := ShareMetadata{Threshold: 2, Description: "greeting", Digest: MessageDigest([]byte("hello "))}
+	path := saveTestTextShares(t, sss, "hello ", meta, dir, "greeting.txt")
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AppendTextShares(path, mustShareText(t, sss, "world")); err != nil {
+		t.Fatal(err)
+	}
+	allShares, loaded, err := loadTextSharesMeta(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := sss.ReconstructText(allShares); err != nil || text != "hello world" {
+		t.Fatalf("got %q, %v; want \"hello world\"", text, err)
+	}
+	if loaded.Description != "greeting" || loaded.Threshold != 2 || loaded.Digest != nil {
+		t.Fatalf("header after append: %+v; want the description and threshold kept and the digest dropped", loaded)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("mode after append: %v, %v; want 0600", info.Mode(), err)
+	}
+
+	// A file without a final newline still gets the new shares on their own lines
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.TrimSuffix(data, []byte("\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendTextShares(path, mustShareText(t, sss, "!")); err != nil {
+		t.Fatal(err)
+	}
+	if allShares, err = loadTextShares(path); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := sss.ReconstructText(allShares); err != nil || text != "hello world!" {
+		t.Fatalf("got %q, %v; want \"hello world!\"", text, err)
+	}
+
+	// Too few shares for the header's threshold leaves the file untouched
+	before, _ := os.ReadFile(path)
+	if err := AppendTextShares(path, subsetShares(mustShareText(t, sss, "?"), 0)); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("one share per character: got %v, want ErrInsufficientShares", err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, before) {
+		t.Fatal("failed append changed the file")
+	}
+	if leftovers, _ := filepath.Glob(path + ".tmp*"); len(leftovers) != 0 {
+		t.Fatalf("failed append left %v behind", leftovers)
+	}
+}
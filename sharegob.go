@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// GobShareHeader precedes the shares in a gob share file
+type GobShareHeader struct {
+	Threshold int
+	Prime     string
+	Count     int // number of secrets that follow
+}
+
+// Secrets per encoded block; batching keeps per-value gob overhead low
+const gobBlockSize = 4096
+
+// gobBlock is the compact on-disk form of consecutive secrets' shares
+type gobBlock struct {
+	Counts []int // shares per secret
+	X      []uint64
+	Y      [][]byte // big-endian
+}
+
+// SaveTextSharesGob writes shares with encoding/gob, which is smaller and
+// faster to load than the decimal text format. Secrets are encoded in
+// fixed-size blocks so files can be streamed. The header records prime, the
+// field the shares belong to.
+func SaveTextSharesGob(allShares [][]Point, threshold int, prime *big.Int, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	enc := gob.NewEncoder(writer)
+
+	header := GobShareHeader{Threshold: threshold, Prime: prime.String(), Count: len(allShares)}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+	for start := 0; start < len(allShares); start += gobBlockSize {
+		var block gobBlock
+		for _, shares := range allShares[start:min(start+gobBlockSize, len(allShares))] {
+			block.Counts = append(block.Counts, len(shares))
+			for _, p := range shares {
+				block.X = append(block.X, p.X.Uint64())
+				block.Y = append(block.Y, p.Y.Bytes())
+			}
+		}
+		if err := enc.Encode(block); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// LoadTextSharesGob reads a file written by SaveTextSharesGob. The shares'
+// prime is in the returned header; pass it to SetPrime before reconstructing.
+func LoadTextSharesGob(filename string) ([][]Point, GobShareHeader, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, GobShareHeader{}, err
+	}
+	defer file.Close()
+
+	dec := gob.NewDecoder(bufio.NewReader(file))
+
+	var header GobShareHeader
+	if err := dec.Decode(&header); err != nil {
+		return nil, header, err
+	}
+	if prime, ok := new(big.Int).SetString(header.Prime, 10); !ok || !prime.ProbablyPrime(32) {
+		return nil, header, fmt.Errorf("%w: %q", ErrInvalidPrime, header.Prime)
+	}
+
+	if header.Count < 0 {
+		return nil, header, fmt.Errorf("invalid secret count %d", header.Count)
+	}
+
+	// Count is read from the file, so grow block by block instead of
+	// preallocating it
+	var allShares [][]Point
+	for len(allShares) < header.Count {
+		var block gobBlock
+		if err := dec.Decode(&block); err != nil {
+			return allShares, header, fmt.Errorf("secret %d of %d: %w", len(allShares), header.Count, err)
+		}
+		if len(block.X) != len(block.Y) {
+			return allShares, header, fmt.Errorf("secret %d of %d: mismatched share fields", len(allShares), header.Count)
+		}
+
+		k := 0
+		for _, n := range block.Counts {
+			if n < 0 || k+n > len(block.X) {
+				return allShares, header, fmt.Errorf("secret %d of %d: bad share count", len(allShares), header.Count)
+			}
+			shares := make([]Point, n)
+			for j := range shares {
+				shares[j] = Point{X: new(big.Int).SetUint64(block.X[k]), Y: new(big.Int).SetBytes(block.Y[k])}
+				k++
+			}
+			allShares = append(allShares, shares)
+		}
+	}
+	return allShares, header, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTextSharesGobLargeField(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if err := sss.SetPrime(Prime127); err != nil {
+		t.Fatal(err)
+	}
+	allShares, err := sss.ShareText("gob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "shares.gob")
+	if err := SaveTextSharesGob(allShares, 2, sss.prime, path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, header, err := LoadTextSharesGob(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Prime != Prime127.String() {
+		t.Fatalf("header prime = %s, want %s", header.Prime, Prime127)
+	}
+	if text, err := sss.ReconstructText(loaded); err != nil || text != "gob" {
+		t.Fatalf("got %q, %v; want \"gob\"", text, err)
+	}
+}
+
+func TestTextSharesGobSmallerAndFaster(t *testing.T) {
+	data := make([]byte, 10*1024)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	sss := newTestSharing(t, 3, 5)
+	allShares, err := sss.ShareArbitraryBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	textPath, gobPath := filepath.Join(dir, "shares.txt"), filepath.Join(dir, "shares.gob")
+	if err := saveTextShares(allShares, textPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveTextSharesGob(allShares, 3, sss.prime, gobPath); err != nil {
+		t.Fatal(err)
+	}
+
+	textInfo, err := os.Stat(textPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gobInfo, err := os.Stat(gobPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gobInfo.Size() >= textInfo.Size() {
+		t.Fatalf("gob file is %d bytes, text file %d", gobInfo.Size(), textInfo.Size())
+	}
+
+	// Compare the best of a few loads so a single slow run doesn't decide it
+	fastest := func(load func() error) time.Duration {
+		best := time.Duration(1<<63 - 1)
+		for range 5 {
+			start := time.Now()
+			if err := load(); err != nil {
+				t.Fatal(err)
+			}
+			best = min(best, time.Since(start))
+		}
+		return best
+	}
+	textTime := fastest(func() error { _, err := loadTextShares(textPath); return err })
+	gobTime := fastest(func() error {
+		loaded, _, err := LoadTextSharesGob(gobPath)
+		if err == nil && len(loaded) != len(data) {
+			err = errors.New("wrong number of secrets")
+		}
+		return err
+	})
+	t.Logf("text: %d bytes, %v; gob: %d bytes, %v", textInfo.Size(), textTime, gobInfo.Size(), gobTime)
+	if gobTime >= textTime {
+		t.Fatalf("gob load took %v, text load %v", gobTime, textTime)
+	}
+}
+
+// writeGobHeader writes a gob share file holding only header
+func writeGobHeader(t *testing.T, header GobShareHeader) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "header.gob")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := gob.NewEncoder(file).Encode(header); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadTextSharesGobBadCount(t *testing.T) {
+	prime := Prime31.String()
+	if _, _, err := LoadTextSharesGob(writeGobHeader(t, GobShareHeader{Threshold: 2, Prime: prime, Count: -1})); err == nil {
+		t.Error("negative count accepted")
+	}
+	// A count far beyond the data must fail on the missing blocks, not
+	// try to allocate room for them first
+	_, _, err := LoadTextSharesGob(writeGobHeader(t, GobShareHeader{Threshold: 2, Prime: prime, Count: 1 << 62}))
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("huge count: got %v, want io.EOF", err)
+	}
+}
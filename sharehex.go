@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidShareHex is returned when a hex-encoded share cannot be parsed
+var ErrInvalidShareHex = errors.New("invalid hex share")
+
+// shareHexWidth is the number of hex digits needed to hold any element of
+// the field of prime
+func shareHexWidth(prime *big.Int) int {
+	return (prime.BitLen() + 3) / 4
+}
+
+// EncodeShareHex encodes a share over the field of prime as
+// "<x-hex>-<y-hex>". Both parts are zero-padded to the same width so every
+// share has equal length.
+func EncodeShareHex(s Point, prime *big.Int) string {
+	width := shareHexWidth(prime)
+	return fmt.Sprintf("%0*x-%0*x", width, s.X, width, s.Y)
+}
+
+// DecodeShareHex parses a share produced by EncodeShareHex
+func DecodeShareHex(encoded string) (Point, error) {
+	parts := strings.Split(encoded, "-")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return Point{}, fmt.Errorf("%w: expected <x>-<y>, got %q", ErrInvalidShareHex, encoded)
+	}
+
+	x, ok := new(big.Int).SetString(parts[0], 16)
+	if !ok {
+		return Point{}, fmt.Errorf("%w: bad x value %q", ErrInvalidShareHex, parts[0])
+	}
+	y, ok := new(big.Int).SetString(parts[1], 16)
+	if !ok {
+		return Point{}, fmt.Errorf("%w: bad y value %q", ErrInvalidShareHex, parts[1])
+	}
+
+	return Point{X: x, Y: y}, nil
+}
+
+// ParseShareHex decodes user-supplied input and validates the share against
+// the field of prime
+func ParseShareHex(s string, prime *big.Int) (Point, error) {
+	s = strings.TrimSpace(s)
+
+	width := shareHexWidth(prime)
+	if len(s) != 2*width+1 {
+		return Point{}, fmt.Errorf("%w: expected %d characters, got %d", ErrInvalidShareHex, 2*width+1, len(s))
+	}
+
+	p, err := DecodeShareHex(s)
+	if err != nil {
+		return Point{}, err
+	}
+
+	if p.X.Sign() <= 0 {
+		return Point{}, fmt.Errorf("%w: x must be positive", ErrInvalidShareHex)
+	}
+	if p.Y.Sign() < 0 || p.Y.Cmp(prime) >= 0 {
+		return Point{}, fmt.Errorf("%w: y out of range", ErrInvalidShareHex)
+	}
+
+	return p, nil
+}
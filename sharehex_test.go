@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestShareHexRoundTrip(t *testing.T) {
+	for _, prime := range []*big.Int{Prime31, Prime61, Prime127} {
+		share := Point{X: big.NewInt(3), Y: new(big.Int).Sub(prime, big.NewInt(1))}
+		encoded := EncodeShareHex(share, prime)
+		if want := 2*shareHexWidth(prime) + 1; len(encoded) != want {
+			t.Errorf("%d-bit prime: %q has %d characters, want %d", prime.BitLen(), encoded, len(encoded), want)
+		}
+		got, err := ParseShareHex(encoded, prime)
+		if err != nil || got.X.Cmp(share.X) != 0 || got.Y.Cmp(share.Y) != 0 {
+			t.Errorf("%d-bit prime: ParseShareHex(%q) = %v, %v; want %v", prime.BitLen(), encoded, got, err, share)
+		}
+	}
+
+	// A Prime61 value does not fit the default field
+	big61 := EncodeShareHex(Point{X: big.NewInt(1), Y: new(big.Int).Sub(Prime61, big.NewInt(1))}, Prime61)
+	if _, err := ParseShareHex(big61, Prime31); !errors.Is(err, ErrInvalidShareHex) {
+		t.Errorf("Prime61 share parsed over Prime31: got %v, want ErrInvalidShareHex", err)
+	}
+}
+
+func TestArmorShareLargeField(t *testing.T) {
+	points := []Point{
+		{X: big.NewInt(2), Y: new(big.Int).Sub(Prime127, big.NewInt(1))},
+		{X: big.NewInt(2), Y: big.NewInt(7)},
+	}
+	x, got, err := UnarmorShareChunks(chunkArmoredShare(ArmorShare(2, points, Prime127)), Prime127)
+	if err != nil || x != 2 || len(got) != len(points) {
+		t.Fatalf("UnarmorShareChunks = %d, %v, %v", x, got, err)
+	}
+	for i := range points {
+		if got[i].Y.Cmp(points[i].Y) != 0 {
+			t.Errorf("value %d = %s, want %s", i, got[i].Y, points[i].Y)
+		}
+	}
+}
+
+func TestEncodeShareHexEqualLength(t *testing.T) {
+	shares := []Point{
+		{X: big.NewInt(1), Y: big.NewInt(0)},
+		{X: big.NewInt(2), Y: big.NewInt(255)},
+		{X: big.NewInt(255), Y: big.NewInt(65536)},
+		{X: big.NewInt(3), Y: new(big.Int).Sub(Prime31, big.NewInt(1))},
+	}
+	want := len(EncodeShareHex(shares[0], Prime31))
+	for _, share := range shares {
+		encoded := EncodeShareHex(share, Prime31)
+		if len(encoded) != want {
+			t.Errorf("%v encodes to %q, %d characters; want %d", share, encoded, len(encoded), want)
+		}
+		got, err := DecodeShareHex(encoded)
+		if err != nil || got.X.Cmp(share.X) != 0 || got.Y.Cmp(share.Y) != 0 {
+			t.Errorf("DecodeShareHex(%q) = %v, %v; want %v", encoded, got, err, share)
+		}
+	}
+
+	for _, bad := range []string{"", "12", "-0a", "0a-", "zz-01", "01-zz", "01-02-03"} {
+		if _, err := DecodeShareHex(bad); !errors.Is(err, ErrInvalidShareHex) {
+			t.Errorf("DecodeShareHex(%q): got %v, want ErrInvalidShareHex", bad, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// The protobuf encoding is written by hand against proto/shares.proto so the
+// tree needs no generated code or protobuf runtime. Output is wire-compatible
+// with the generated types for that schema.
+
+// ErrInvalidProto is returned when protobuf share data cannot be parsed
+var ErrInvalidProto = errors.New("invalid protobuf share data")
+
+// Largest single message accepted by ReadSharesProto: room for a few
+// hundred thousand large-field shares, far beyond any real share set
+const maxProtoMessageSize = 64 << 20
+
+// Protobuf wire types
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// appendProtoBytes appends a length-delimited field
+func appendProtoBytes(buf []byte, field int, data []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(field<<3|protoBytes))
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+// MarshalSharesProto encodes shares as a ShareFile message
+func MarshalSharesProto(allShares [][]Point) []byte {
+	var out, secret, share []byte
+	for _, shares := range allShares {
+		secret = secret[:0]
+		for _, p := range shares {
+			share = binary.AppendUvarint(share[:0], 1<<3|protoVarint)
+			share = binary.AppendUvarint(share, p.X.Uint64())
+			share = appendProtoBytes(share, 2, p.Y.Bytes())
+			secret = appendProtoBytes(secret, 1, share)
+		}
+		out = appendProtoBytes(out, 1, secret)
+	}
+	return out
+}
+
+// UnmarshalSharesProto decodes a ShareFile message
+func UnmarshalSharesProto(data []byte) ([][]Point, error) {
+	var allShares [][]Point
+	err := walkProto(data, func(field int, value []byte) error {
+		if field != 1 {
+			return nil
+		}
+		shares, err := unmarshalSecretProto(value)
+		if err != nil {
+			return err
+		}
+		allShares = append(allShares, shares)
+		return nil
+	})
+	return allShares, err
+}
+
+func unmarshalSecretProto(data []byte) ([]Point, error) {
+	var shares []Point
+	err := walkProto(data, func(field int, value []byte) error {
+		if field != 1 {
+			return nil
+		}
+		p := Point{X: new(big.Int), Y: new(big.Int)}
+		err := walkProto(value, func(field int, value []byte) error {
+			switch field {
+			case 1:
+				x, n := binary.Uvarint(value)
+				if n <= 0 {
+					return fmt.Errorf("%w: bad x", ErrInvalidProto)
+				}
+				p.X.SetUint64(x)
+			case 2:
+				p.Y.SetBytes(value)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		shares = append(shares, p)
+		return nil
+	})
+	return shares, err
+}
+
+// walkProto calls fn for every field in a message. Varint values are passed
+// still encoded; unknown fields are skipped by the callers.
+func walkProto(data []byte, fn func(field int, value []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 || key>>3 == 0 {
+			return fmt.Errorf("%w: bad field key", ErrInvalidProto)
+		}
+		data = data[n:]
+
+		var value []byte
+		switch key & 7 {
+		case protoVarint:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return fmt.Errorf("%w: bad varint", ErrInvalidProto)
+			}
+			value, data = data[:n], data[n:]
+		case protoFixed64, protoFixed32:
+			size := 8
+			if key&7 == protoFixed32 {
+				size = 4
+			}
+			if len(data) < size {
+				return fmt.Errorf("%w: truncated field", ErrInvalidProto)
+			}
+			value, data = data[:size], data[size:]
+		case protoBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return fmt.Errorf("%w: truncated field", ErrInvalidProto)
+			}
+			data = data[n:]
+			value, data = data[:length], data[length:]
+		default:
+			return fmt.Errorf("%w: unsupported wire type %d", ErrInvalidProto, key&7)
+		}
+
+		if err := fn(int(key>>3), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSharesProto writes shares as a varint length-prefixed ShareFile, the
+// framing used by protobuf's delimited streams
+func WriteSharesProto(w io.Writer, allShares [][]Point) error {
+	msg := MarshalSharesProto(allShares)
+	if _, err := w.Write(binary.AppendUvarint(nil, uint64(len(msg)))); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
+
+// byteReader reads one byte at a time so no data past the prefix is consumed
+type byteReader struct {
+	io.Reader
+}
+
+func (r byteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(r.Reader, b[:])
+	return b[0], err
+}
+
+// ReadSharesProto reads one message written by WriteSharesProto, leaving r
+// positioned at the next message
+func ReadSharesProto(r io.Reader) ([][]Point, error) {
+	length, err := binary.ReadUvarint(byteReader{r})
+	if err != nil {
+		return nil, err
+	}
+	if length > maxProtoMessageSize {
+		return nil, fmt.Errorf("%w: message of %d bytes is too large", ErrInvalidProto, length)
+	}
+
+	// The prefix is untrusted, so let the buffer grow with the data that
+	// actually arrives instead of allocating length bytes up front
+	msg, err := io.ReadAll(io.LimitReader(r, int64(length)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(msg)) < length {
+		return nil, fmt.Errorf("%w: message has %d of %d bytes", io.ErrUnexpectedEOF, len(msg), length)
+	}
+	return UnmarshalSharesProto(msg)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"runtime"
+	"testing"
+)
+
+func TestSharesProtoRoundTrip(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if err := sss.SetPrime(Prime127); err != nil {
+		t.Fatal(err)
+	}
+	first := mustShareText(t, sss, "proto")
+	second := mustShareText(t, sss, "two")
+
+	var stream bytes.Buffer
+	for _, allShares := range [][][]Point{first, second} {
+		if err := WriteSharesProto(&stream, allShares); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, want := range []string{"proto", "two"} {
+		allShares, err := ReadSharesProto(&stream)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text, err := sss.ReconstructText(allShares); err != nil || text != want {
+			t.Fatalf("got %q, %v; want %q", text, err, want)
+		}
+	}
+	if _, err := ReadSharesProto(&stream); err != io.EOF {
+		t.Fatalf("after the last message: got %v, want io.EOF", err)
+	}
+}
+
+func TestSharesProtoWireFormat(t *testing.T) {
+	// ShareFile{secrets: [Secret{shares: [Share{x: 1, y: 0x0102}]}]} as
+	// encoded by the generated code for proto/shares.proto
+	wire := []byte{0x0a, 0x08, 0x0a, 0x06, 0x08, 0x01, 0x12, 0x02, 0x01, 0x02}
+	allShares := [][]Point{{{X: big.NewInt(1), Y: big.NewInt(0x0102)}}}
+	if got := MarshalSharesProto(allShares); !bytes.Equal(got, wire) {
+		t.Fatalf("MarshalSharesProto = % x, want % x", got, wire)
+	}
+
+	// An unknown field 7 on the share is skipped
+	extended := []byte{0x0a, 0x0a, 0x0a, 0x08, 0x08, 0x01, 0x12, 0x02, 0x01, 0x02, 0x38, 0x05}
+	got, err := UnmarshalSharesProto(extended)
+	if err != nil || len(got) != 1 || len(got[0]) != 1 || got[0][0].X.Int64() != 1 || got[0][0].Y.Int64() != 0x0102 {
+		t.Fatalf("UnmarshalSharesProto = %v, %v", got, err)
+	}
+
+	for name, data := range map[string][]byte{
+		"truncated": wire[:len(wire)-1],
+		"field 0":   {0x02, 0x00},
+		"group":     {0x0b},
+	} {
+		if _, err := UnmarshalSharesProto(data); !errors.Is(err, ErrInvalidProto) {
+			t.Errorf("%s: got %v, want ErrInvalidProto", name, err)
+		}
+	}
+	if _, err := ReadSharesProto(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0x0f})); !errors.Is(err, ErrInvalidProto) {
+		t.Fatalf("4 GB length prefix: got %v, want ErrInvalidProto", err)
+	}
+}
+
+func TestReadSharesProtoShortBody(t *testing.T) {
+	// A prefix just under the cap followed by three bytes of data
+	var prefix [binary.MaxVarintLen64]byte
+	input := append(prefix[:binary.PutUvarint(prefix[:], maxProtoMessageSize-1)], 1, 2, 3)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := ReadSharesProto(bytes.NewReader(input))
+	runtime.ReadMemStats(&after)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("short body: got %v, want io.ErrUnexpectedEOF", err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Fatalf("allocated %d bytes for a 3-byte body", allocated)
+	}
+
+	var over [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(over[:], maxProtoMessageSize+1)
+	if _, err := ReadSharesProto(bytes.NewReader(over[:n])); !errors.Is(err, ErrInvalidProto) {
+		t.Fatalf("prefix above the cap: got %v, want ErrInvalidProto", err)
+	}
+}
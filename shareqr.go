@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Decoding QR images is out of scope: scan the codes with any QR reader and
+// pass the resulting text to UnarmorShareChunks.
+
+// ErrInvalidArmoredShare is returned when an armored share cannot be parsed
+var ErrInvalidArmoredShare = errors.New("invalid armored share")
+
+const (
+	// Bytes of armored share text carried by each QR code
+	qrChunkSize = 180
+	// Pixels per QR module and width of the quiet zone in modules
+	qrModuleSize = 8
+	qrQuietZone  = 4
+)
+
+// ArmorShare encodes one participant's shares over the field of prime as
+// "<x-hex>:<y-hex>...", with every y zero-padded to the same width
+func ArmorShare(participantX int, points []Point, prime *big.Int) string {
+	width := shareHexWidth(prime)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%0*x:", width, participantX)
+	for _, p := range points {
+		fmt.Fprintf(&b, "%0*x", width, p.Y)
+	}
+	return b.String()
+}
+
+// UnarmorShare parses a string produced by ArmorShare with the same prime
+func UnarmorShare(armored string, prime *big.Int) (int, []Point, error) {
+	width := shareHexWidth(prime)
+
+	xHex, body, ok := strings.Cut(strings.TrimSpace(armored), ":")
+	if !ok || len(body)%width != 0 {
+		return 0, nil, fmt.Errorf("%w: malformed share string", ErrInvalidArmoredShare)
+	}
+	x, err := strconv.ParseInt(xHex, 16, 64)
+	if err != nil || x <= 0 {
+		return 0, nil, fmt.Errorf("%w: bad participant %q", ErrInvalidArmoredShare, xHex)
+	}
+
+	points := make([]Point, 0, len(body)/width)
+	for i := 0; i < len(body); i += width {
+		y, ok := new(big.Int).SetString(body[i:i+width], 16)
+		if !ok || y.Cmp(prime) >= 0 {
+			return 0, nil, fmt.Errorf("%w: bad value %q", ErrInvalidArmoredShare, body[i:i+width])
+		}
+		points = append(points, Point{X: big.NewInt(x), Y: y})
+	}
+	return int(x), points, nil
+}
+
+// chunkArmoredShare splits armored text into "i/n <text>" pieces that each
+// fit in a single QR code
+func chunkArmoredShare(armored string) []string {
+	n := (len(armored) + qrChunkSize - 1) / qrChunkSize
+	if n == 0 {
+		n = 1
+	}
+
+	chunks := make([]string, n)
+	for i := range chunks {
+		end := min((i+1)*qrChunkSize, len(armored))
+		chunks[i] = fmt.Sprintf("%d/%d %s", i+1, n, armored[i*qrChunkSize:end])
+	}
+	return chunks
+}
+
+// UnarmorShareChunks reassembles scanned chunks, given in any order, and
+// parses the resulting share
+func UnarmorShareChunks(chunks []string, prime *big.Int) (int, []Point, error) {
+	pieces := make(map[int]string, len(chunks))
+	total := 0
+
+	for _, chunk := range chunks {
+		header, text, ok := strings.Cut(strings.TrimSpace(chunk), " ")
+		if !ok {
+			return 0, nil, fmt.Errorf("%w: chunk has no sequence header", ErrInvalidArmoredShare)
+		}
+		var i, n int
+		if _, err := fmt.Sscanf(header, "%d/%d", &i, &n); err != nil || i < 1 || i > n {
+			return 0, nil, fmt.Errorf("%w: bad sequence header %q", ErrInvalidArmoredShare, header)
+		}
+		if total != 0 && n != total {
+			return 0, nil, fmt.Errorf("%w: chunks from different shares", ErrInvalidArmoredShare)
+		}
+		total = n
+		pieces[i] = text
+	}
+
+	if total == 0 || len(pieces) != total {
+		return 0, nil, fmt.Errorf("%w: have %d of %d chunks", ErrInvalidArmoredShare, len(pieces), total)
+	}
+
+	order := make([]int, 0, total)
+	for i := range pieces {
+		order = append(order, i)
+	}
+	sort.Ints(order)
+
+	var b strings.Builder
+	for _, i := range order {
+		b.WriteString(pieces[i])
+	}
+	return UnarmorShare(b.String(), prime)
+}
+
+// EncodeShareQR writes a participant's armored share as QR code PNGs for
+// printing. Shares too large for one code are split into numbered chunks
+// written to <name>_1.png, <name>_2.png and so on; a share that fits is
+// written to outputPath itself. It returns the paths written.
+func EncodeShareQR(participantX int, points []Point, prime *big.Int, outputPath string) ([]string, error) {
+	chunks := chunkArmoredShare(ArmorShare(participantX, points, prime))
+
+	paths := []string{outputPath}
+	if len(chunks) > 1 {
+		ext := filepath.Ext(outputPath)
+		base := strings.TrimSuffix(outputPath, ext)
+		paths = make([]string, len(chunks))
+		for i := range chunks {
+			paths[i] = fmt.Sprintf("%s_%d%s", base, i+1, ext)
+		}
+	}
+
+	for i, chunk := range chunks {
+		modules, err := encodeQR([]byte(chunk))
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQRImage(modules, paths[i]); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// writeQRImage renders a module grid as a black-on-white PNG
+func writeQRImage(modules [][]bool, outputPath string) error {
+	size := (len(modules) + 2*qrQuietZone) * qrModuleSize
+	img := image.NewGray(image.Rect(0, 0, size, size))
+
+	for py := 0; py < size; py++ {
+		for px := 0; px < size; px++ {
+			x := px/qrModuleSize - qrQuietZone
+			y := py/qrModuleSize - qrQuietZone
+			c := color.Gray{Y: 255}
+			if x >= 0 && y >= 0 && x < len(modules) && y < len(modules) && modules[y][x] {
+				c = color.Gray{Y: 0}
+			}
+			img.SetGray(px, py, c)
+		}
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"image/png"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readQRModules loads a QR PNG and samples its module grid, finding the
+// module size from the top-left finder pattern
+func readQRModules(t *testing.T, path string) [][]bool {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("%s is not a valid PNG: %v", path, err)
+	}
+
+	dark := func(x, y int) bool {
+		r, g, b, _ := img.At(x, y).RGBA()
+		return r+g+b < 3*0x8000
+	}
+	bounds := img.Bounds()
+	left, top, right := -1, -1, -1
+	for y := bounds.Min.Y; y < bounds.Max.Y && top < 0; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if dark(x, y) {
+				left, top = x, y
+				break
+			}
+		}
+	}
+	if top < 0 {
+		t.Fatal("blank image")
+	}
+	for x := bounds.Max.X - 1; x >= left; x-- {
+		if dark(x, top) {
+			right = x
+			break
+		}
+	}
+	run := 0
+	for dark(left+run, top) {
+		run++
+	}
+	moduleSize := run / 7
+	size := (right - left + 1) / moduleSize
+	if moduleSize == 0 || (size-17)%4 != 0 {
+		t.Fatalf("symbol of %d pixels with %d-pixel modules is not a QR size", right-left+1, moduleSize)
+	}
+
+	modules := make([][]bool, size)
+	for y := range modules {
+		modules[y] = make([]bool, size)
+		for x := range modules[y] {
+			modules[y][x] = dark(left+x*moduleSize+moduleSize/2, top+y*moduleSize+moduleSize/2)
+		}
+	}
+	return modules
+}
+
+// decodeQR decodes a byte-mode, level M symbol, checking the finder
+// patterns, the format information and every block's Reed-Solomon code
+func decodeQR(t *testing.T, modules [][]bool) []byte {
+	t.Helper()
+	size := len(modules)
+	version := (size - 17) / 4
+
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for dy := range 7 {
+			for dx := range 7 {
+				ring := max(abs(dx-3), abs(dy-3))
+				if want := ring != 2; modules[corner[1]+dy][corner[0]+dx] != want {
+					t.Fatalf("finder pattern at %v is broken at (%d, %d)", corner, dx, dy)
+				}
+			}
+		}
+	}
+
+	// Format information around the top-left finder
+	format := 0
+	bit := func(i int, x, y int) {
+		if modules[y][x] {
+			format |= 1 << i
+		}
+	}
+	for i := 0; i <= 5; i++ {
+		bit(i, 8, i)
+	}
+	bit(6, 8, 7)
+	bit(7, 8, 8)
+	bit(8, 7, 8)
+	for i := 9; i < 15; i++ {
+		bit(i, 14-i, 8)
+	}
+	format ^= 0x5412
+	data := format >> 10
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	if rem&0x3FF != format&0x3FF {
+		t.Fatalf("format information %015b fails its BCH check", format)
+	}
+	if level := data >> 3; level != qrLevelMBits {
+		t.Fatalf("error correction level bits %02b, want level M", level)
+	}
+	mask := data & 7
+
+	// Unmask the data modules and read them back in zigzag order
+	layout := newQRSymbol(version)
+	layout.drawFunctionPatterns(version)
+	for y := range size {
+		for x := range size {
+			if !layout.isFunction[y][x] {
+				layout.modules[y][x] = modules[y][x]
+			}
+		}
+	}
+	layout.applyMask(mask)
+
+	raw := make([]byte, qrNumRawDataModules(version)/8)
+	i := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if !layout.isFunction[y][x] && i < len(raw)*8 {
+					if layout.modules[y][x] {
+						raw[i>>3] |= 1 << (7 - uint(i&7))
+					}
+					i++
+				}
+			}
+		}
+	}
+
+	// De-interleave the blocks and check each one's syndromes
+	numBlocks, eccLen := qrNumBlocks[version], qrECCPerBlock[version]
+	numShort := numBlocks - len(raw)%numBlocks
+	shortLen := len(raw) / numBlocks
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortLen; i++ {
+		for j := range blocks {
+			// Short blocks have no codeword where long blocks hold their
+			// extra data byte
+			if i == shortLen-eccLen && j < numShort {
+				continue
+			}
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+	var codewords []byte
+	for j, block := range blocks {
+		for s := range eccLen {
+			root := byte(1)
+			for range s {
+				root = qrGFMultiply(root, 2)
+			}
+			var syndrome byte
+			for _, b := range block {
+				syndrome = qrGFMultiply(syndrome, root) ^ b
+			}
+			if syndrome != 0 {
+				t.Fatalf("block %d has a non-zero syndrome %d", j, s)
+			}
+		}
+		codewords = append(codewords, block[:len(block)-eccLen]...)
+	}
+
+	// Byte mode: 0100, the length, then the bytes
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for range n {
+			v = v<<1 | int(codewords[pos>>3]>>(7-uint(pos&7))&1)
+			pos++
+		}
+		return v
+	}
+	if mode := read(4); mode != 0b0100 {
+		t.Fatalf("mode indicator %04b, want byte mode", mode)
+	}
+	payload := make([]byte, read(qrCountBits(version)))
+	for i := range payload {
+		payload[i] = byte(read(8))
+	}
+	return payload
+}
+
+func TestEncodeShareQR(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	allShares, err := sss.ShareText("qr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := []Point{allShares[0][1], allShares[1][1]}
+	armored := ArmorShare(2, points, sss.prime)
+
+	paths, err := EncodeShareQR(2, points, sss.prime, filepath.Join(t.TempDir(), "share.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("small share written to %d codes, want 1", len(paths))
+	}
+	scanned := string(decodeQR(t, readQRModules(t, paths[0])))
+	if want := "1/1 " + armored; scanned != want {
+		t.Fatalf("QR code holds %q, want %q", scanned, want)
+	}
+
+	x, got, err := UnarmorShareChunks([]string{scanned}, sss.prime)
+	if err != nil || x != 2 || len(got) != 2 {
+		t.Fatalf("UnarmorShareChunks = %d, %v, %v", x, got, err)
+	}
+	for i := range got {
+		if got[i].Y.Cmp(points[i].Y) != 0 {
+			t.Errorf("value %d = %s, want %s", i, got[i].Y, points[i].Y)
+		}
+	}
+}
+
+func TestEncodeShareQRChunks(t *testing.T) {
+	// Enough values to need several codes
+	points := make([]Point, 60)
+	for i := range points {
+		points[i] = Point{X: big.NewInt(1), Y: big.NewInt(int64(i * 1000003))}
+	}
+	paths, err := EncodeShareQR(1, points, Prime31, filepath.Join(t.TempDir(), "share.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) < 2 {
+		t.Fatalf("%d codes for %d values, want several", len(paths), len(points))
+	}
+
+	chunks := make([]string, len(paths))
+	for i, path := range paths {
+		// Scan them out of order
+		chunks[len(paths)-1-i] = string(decodeQR(t, readQRModules(t, path)))
+	}
+	x, got, err := UnarmorShareChunks(chunks, Prime31)
+	if err != nil || x != 1 || len(got) != len(points) {
+		t.Fatalf("UnarmorShareChunks = %d, %d values, %v", x, len(got), err)
+	}
+	for i := range got {
+		if got[i].Y.Cmp(points[i].Y) != 0 {
+			t.Errorf("value %d = %s, want %s", i, got[i].Y, points[i].Y)
+		}
+	}
+}
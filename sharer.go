@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrSecretOutOfRange is returned for secrets outside [0, prime)
+var ErrSecretOutOfRange = errors.New("secret must be non-negative and less than the prime")
+
+// SecretSharer is the minimal interface for swappable secret sharing
+// backends. Both methods report failures as errors rather than panics.
+type SecretSharer interface {
+	GenerateShares(secret *big.Int) ([]Point, error)
+	ReconstructSecret(shares []Point) (*big.Int, error)
+}
+
+// shamirSharer adapts ShamirSecretSharing to SecretSharer
+type shamirSharer struct {
+	sss *ShamirSecretSharing
+}
+
+// AsSecretSharer returns sss as a SecretSharer
+func (sss *ShamirSecretSharing) AsSecretSharer() SecretSharer {
+	return shamirSharer{sss}
+}
+
+func (s shamirSharer) GenerateShares(secret *big.Int) ([]Point, error) {
+	if secret.Sign() < 0 || secret.Cmp(s.sss.prime) >= 0 {
+		return nil, ErrSecretOutOfRange
+	}
+	return s.sss.GenerateShares(secret), nil
+}
+
+func (s shamirSharer) ReconstructSecret(shares []Point) (*big.Int, error) {
+	secret, _, err := s.sss.ReconstructSecretVerbose(shares)
+	return secret, err
+}
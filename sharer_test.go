@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestSecretSharer(t *testing.T) {
+	var sharer SecretSharer = newTestSharing(t, 3, 5).AsSecretSharer()
+	secret := big.NewInt(123456)
+	shares, err := sharer.GenerateShares(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("got %d shares, want 5", len(shares))
+	}
+	got, err := sharer.ReconstructSecret(shares[2:])
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("got %v, %v; want %s", got, err, secret)
+	}
+
+	// Failures come back as errors instead of panics
+	for _, bad := range []*big.Int{big.NewInt(-1), new(big.Int).Set(PRIME)} {
+		if _, err := sharer.GenerateShares(bad); !errors.Is(err, ErrSecretOutOfRange) {
+			t.Errorf("secret %s: got %v, want ErrSecretOutOfRange", bad, err)
+		}
+	}
+	if _, err := sharer.ReconstructSecret(shares[:2]); !errors.Is(err, ErrInsufficientShares) {
+		t.Errorf("two of three shares: got %v, want ErrInsufficientShares", err)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// ShareStore holds generated shares in memory until participants collect
+// them. Entries expire after their TTL; a background goroutine evicts them
+// until Close is called. It is safe for concurrent use.
+type ShareStore struct {
+	mu      sync.RWMutex
+	entries map[string]shareStoreEntry
+	done    chan struct{}
+	once    sync.Once
+}
+
+type shareStoreEntry struct {
+	shares  [][]Point
+	expires time.Time
+}
+
+// NewShareStore creates a store that sweeps expired entries every interval
+func NewShareStore(interval time.Duration) *ShareStore {
+	s := &ShareStore{
+		entries: make(map[string]shareStoreEntry),
+		done:    make(chan struct{}),
+	}
+	go s.evictLoop(interval)
+	return s
+}
+
+func (s *ShareStore) evictLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.evictExpired(now)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// evictExpired removes every entry that has expired by now
+func (s *ShareStore) evictExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, id)
+		}
+	}
+}
+
+// Close stops the eviction goroutine; the store remains usable
+func (s *ShareStore) Close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Put stores the shares for a session, replacing any earlier entry
+func (s *ShareStore) Put(sessionID string, shares [][]Point, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[sessionID] = shareStoreEntry{shares: shares, expires: time.Now().Add(ttl)}
+}
+
+// Get returns the shares for a session if present and not yet expired
+func (s *ShareStore) Get(sessionID string) ([][]Point, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[sessionID]
+	if !ok || !time.Now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.shares, true
+}
+
+// Delete removes a session's shares
+func (s *ShareStore) Delete(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, sessionID)
+}
+
+// Collect returns one participant's share of every secret in a session.
+// participantIdx is zero-based, matching the order GenerateShares returns.
+func (s *ShareStore) Collect(sessionID string, participantIdx int) ([]Point, bool) {
+	allShares, ok := s.Get(sessionID)
+	if !ok || participantIdx < 0 {
+		return nil, false
+	}
+
+	collected := make([]Point, len(allShares))
+	for i, shares := range allShares {
+		if participantIdx >= len(shares) {
+			return nil, false
+		}
+		collected[i] = shares[participantIdx]
+	}
+	return collected, true
+}
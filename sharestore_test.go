@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShareStoreConcurrentPutGet(t *testing.T) {
+	store := NewShareStore(time.Millisecond)
+	defer store.Close()
+	sss := newTestSharing(t, 2, 3)
+	allShares := mustShareText(t, sss, "cached")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				id := fmt.Sprintf("session-%d-%d", g, i)
+				store.Put(id, allShares, time.Minute)
+				got, ok := store.Get(id)
+				if !ok || len(got) != len(allShares) {
+					errs <- fmt.Errorf("%s: Get = %d secrets, %v", id, len(got), ok)
+					return
+				}
+				if _, ok := store.Collect(id, 2); !ok {
+					errs <- fmt.Errorf("%s: Collect failed", id)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestShareStoreExpiry(t *testing.T) {
+	store := NewShareStore(time.Hour)
+	defer store.Close()
+	allShares := mustShareText(t, newTestSharing(t, 2, 3), "gone soon")
+
+	store.Put("short", allShares, 10*time.Millisecond)
+	store.Put("long", allShares, time.Hour)
+	time.Sleep(20 * time.Millisecond)
+
+	// Get hides an expired entry before the sweep removes it
+	if _, ok := store.Get("short"); ok {
+		t.Fatal("expired entry returned by Get")
+	}
+	store.evictExpired(time.Now())
+	store.mu.RLock()
+	_, short := store.entries["short"]
+	_, long := store.entries["long"]
+	store.mu.RUnlock()
+	if short || !long {
+		t.Fatalf("after eviction: short present %v, long present %v", short, long)
+	}
+}
+
+func TestShareStoreDeleteAndCollect(t *testing.T) {
+	store := NewShareStore(time.Hour)
+	defer store.Close()
+	sss := newTestSharing(t, 2, 3)
+	allShares := mustShareText(t, sss, "ab")
+	store.Put("s", allShares, time.Hour)
+
+	collected, ok := store.Collect("s", 1)
+	if !ok || len(collected) != 2 || collected[0].X.Cmp(allShares[0][1].X) != 0 || collected[1].Y.Cmp(allShares[1][1].Y) != 0 {
+		t.Fatalf("Collect = %v, %v", collected, ok)
+	}
+	for _, idx := range []int{-1, 3} {
+		if _, ok := store.Collect("s", idx); ok {
+			t.Errorf("Collect(%d) succeeded", idx)
+		}
+	}
+
+	store.Delete("s")
+	if _, ok := store.Get("s"); ok {
+		t.Fatal("Get after Delete found the entry")
+	}
+	if _, ok := store.Collect("s", 0); ok {
+		t.Fatal("Collect after Delete found the entry")
+	}
+	store.Close()
+	store.Close() // a second Close is harmless
+}
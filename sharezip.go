@@ -0,0 +1,86 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+)
+
+// zipEntryName names the entry holding participant i's shares (1-based)
+func zipEntryName(participant int) string {
+	return fmt.Sprintf("share_%d.txt", participant)
+}
+
+// SaveSharesZip writes one text share file per participant into a ZIP
+// archive, as share_1.txt to share_n.txt. Each entry gets meta without its
+// message digest (see holderMetadata), since entries are handed out singly.
+func SaveSharesZip(allShares [][]Point, meta ShareMetadata, outputPath string) error {
+	byParticipant, err := Transpose(allShares)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	meta = holderMetadata(meta)
+	archive := zip.NewWriter(file)
+	for p, shares := range byParticipant {
+		// One share per secret, in the usual secret-major layout
+		column := make([][]Point, len(shares))
+		for i := range shares {
+			column[i] = shares[i : i+1]
+		}
+
+		entry, err := archive.Create(zipEntryName(p + 1))
+		if err != nil {
+			return err
+		}
+		if err := writeTextShares(entry, column, meta); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// LoadSharesZip reads the given participants' entries (1-based) from an
+// archive written by SaveSharesZip and merges them per secret
+func LoadSharesZip(zipPath string, participantIndices []int) ([][]Point, error) {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	var allShares [][]Point
+	for n, participant := range participantIndices {
+		name := zipEntryName(participant)
+		entry, err := archive.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		column, _, _, err := readTextShares(entry)
+		entry.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if n == 0 {
+			allShares = make([][]Point, len(column))
+		} else if len(column) != len(allShares) {
+			return nil, fmt.Errorf("%s: has %d secrets, expected %d", name, len(column), len(allShares))
+		}
+		for i, shares := range column {
+			allShares[i] = append(allShares[i], shares...)
+		}
+	}
+
+	return allShares, nil
+}
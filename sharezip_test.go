@@ -0,0 +1,50 @@
+package main
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveSharesZipRoundTrip(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	allShares, err := sss.ShareText("zipped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "shares.zip")
+	meta := ShareMetadata{Threshold: 3, Digest: MessageDigest([]byte("zipped"))}
+	if err := SaveSharesZip(allShares, meta, path); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	if len(archive.File) != 5 {
+		t.Fatalf("archive has %d entries, want 5", len(archive.File))
+	}
+	for _, f := range archive.File {
+		entry, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(entry)
+		entry.Close()
+		if strings.Contains(string(data), "#digest") {
+			t.Errorf("%s carries the message digest", f.Name)
+		}
+	}
+
+	subset, err := LoadSharesZip(path, []int{2, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := sss.ReconstructText(subset); err != nil || text != "zipped" {
+		t.Fatalf("got %q, %v; want \"zipped\"", text, err)
+	}
+}
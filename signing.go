@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ErrInvalidSignature is returned when a signed share set fails verification
+var ErrInvalidSignature = errors.New("share set signature is invalid")
+
+// SignedShareSet is a share set signed by the dealer that generated it
+type SignedShareSet struct {
+	Shares    []Point
+	Signature []byte // ASN.1 ECDSA signature over shareSetDigest
+	PublicKey []byte // dealer's key in PKIX DER form
+	Timestamp time.Time
+}
+
+// shareSetDigest hashes the shares and timestamp in a fixed text form
+func shareSetDigest(shares []Point, timestamp time.Time) []byte {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%d\n", timestamp.UTC().Format(time.RFC3339Nano), len(shares))
+	for _, share := range shares {
+		fmt.Fprintf(h, "%s %s\n", share.X, share.Y)
+	}
+	return h.Sum(nil)
+}
+
+// ShareAndSign shares a secret and signs the resulting share set
+func (sss *ShamirSecretSharing) ShareAndSign(secret *big.Int, signingKey *ecdsa.PrivateKey) (*SignedShareSet, error) {
+	publicKey, err := x509.MarshalPKIXPublicKey(&signingKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ss := &SignedShareSet{
+		Shares:    sss.GenerateShares(secret),
+		PublicKey: publicKey,
+		Timestamp: time.Now().UTC(),
+	}
+	ss.Signature, err = ecdsa.SignASN1(rand.Reader, signingKey, shareSetDigest(ss.Shares, ss.Timestamp))
+	if err != nil {
+		return nil, err
+	}
+	return ss, nil
+}
+
+// VerifyAndReconstruct checks the set's signature against verifyKey and that
+// every supplied share belongs to the signed set, then reconstructs
+func (sss *ShamirSecretSharing) VerifyAndReconstruct(ss *SignedShareSet, verifyKey *ecdsa.PublicKey, shares []Point) (*big.Int, error) {
+	if !ecdsa.VerifyASN1(verifyKey, shareSetDigest(ss.Shares, ss.Timestamp), ss.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	signed := make(map[string]bool, len(ss.Shares))
+	for _, share := range ss.Shares {
+		signed[share.X.String()+" "+share.Y.String()] = true
+	}
+	for _, share := range shares {
+		if !signed[share.X.String()+" "+share.Y.String()] {
+			return nil, fmt.Errorf("%w: share x = %s is not part of the signed set", ErrInvalidSignature, share.X)
+		}
+	}
+
+	secret, _, err := sss.ReconstructSecretVerbose(shares)
+	return secret, err
+}
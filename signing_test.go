@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestShareAndSign(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sss := newTestSharing(t, 2, 3)
+	secret := big.NewInt(5150)
+	ss, err := sss.ShareAndSign(secret, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	embedded, err := x509.ParsePKIXPublicKey(ss.PublicKey)
+	if err != nil || !key.PublicKey.Equal(embedded) {
+		t.Fatalf("embedded public key = %v, %v", embedded, err)
+	}
+	got, err := sss.VerifyAndReconstruct(ss, &key.PublicKey, ss.Shares[1:])
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("valid signature: got %v, %v; want %s", got, err, secret)
+	}
+}
+
+func TestVerifyAndReconstructRejects(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sss := newTestSharing(t, 2, 3)
+	ss, err := sss.ShareAndSign(big.NewInt(5150), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sss.VerifyAndReconstruct(ss, &other.PublicKey, ss.Shares); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong key: got %v, want ErrInvalidSignature", err)
+	}
+
+	tampered := *ss
+	tampered.Shares = append([]Point(nil), ss.Shares...)
+	tampered.Shares[0] = Point{X: ss.Shares[0].X, Y: new(big.Int).Add(ss.Shares[0].Y, big.NewInt(1))}
+	if _, err := sss.VerifyAndReconstruct(&tampered, &key.PublicKey, tampered.Shares); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("tampered share set: got %v, want ErrInvalidSignature", err)
+	}
+
+	backdated := *ss
+	backdated.Timestamp = ss.Timestamp.Add(-time.Hour)
+	if _, err := sss.VerifyAndReconstruct(&backdated, &key.PublicKey, ss.Shares); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("changed timestamp: got %v, want ErrInvalidSignature", err)
+	}
+
+	// A validly signed set does not vouch for a share outside it
+	forged := []Point{ss.Shares[0], {X: ss.Shares[1].X, Y: big.NewInt(1)}}
+	if _, err := sss.VerifyAndReconstruct(ss, &key.PublicKey, forged); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("unsigned share: got %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrInvalidTableName is returned for table names that are not plain SQL
+// identifiers. Table names cannot be passed as query parameters, so anything
+// else is refused rather than quoted.
+var ErrInvalidTableName = errors.New("table name must be letters, digits and underscores, not starting with a digit")
+
+// checkTableName allows only [A-Za-z_][A-Za-z0-9_]*
+func checkTableName(name string) error {
+	if name == "" {
+		return ErrInvalidTableName
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidTableName, name)
+		}
+	}
+	return nil
+}
+
+// SaveSharesToDB stores shares in tableName, one row per share with columns
+// (secret_index, share_index, x, y). Coordinates are decimal TEXT so values
+// of any size survive. The table is created if needed and its previous rows
+// are replaced, all in one transaction. Queries use ? placeholders, as
+// SQLite and MySQL do; db may use any database/sql driver that accepts them.
+func SaveSharesToDB(db *sql.DB, tableName string, allShares [][]Point) (err error) {
+	if err := checkTableName(tableName); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec(`CREATE TABLE IF NOT EXISTS ` + tableName + ` (
+		secret_index INTEGER NOT NULL,
+		share_index INTEGER NOT NULL,
+		x TEXT NOT NULL,
+		y TEXT NOT NULL,
+		PRIMARY KEY (secret_index, share_index)
+	)`); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`DELETE FROM ` + tableName); err != nil {
+		return err
+	}
+
+	insert, err := tx.Prepare(`INSERT INTO ` + tableName + ` (secret_index, share_index, x, y) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+
+	for i, shares := range allShares {
+		for j, p := range shares {
+			if p.X == nil || p.Y == nil {
+				return fmt.Errorf("secret %d share %d: %w: missing coordinate", i, j, ErrInvalidShare)
+			}
+			if _, err = insert.Exec(i, j, p.X.String(), p.Y.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadSharesFromDB reads shares saved by SaveSharesToDB. Secret indices must
+// run from 0 without gaps, since a missing secret would silently drop a
+// byte from the reconstruction.
+func LoadSharesFromDB(db *sql.DB, tableName string) ([][]Point, error) {
+	if err := checkTableName(tableName); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT secret_index, x, y FROM ` + tableName + ` ORDER BY secret_index, share_index`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var allShares [][]Point
+	for rows.Next() {
+		var secret int
+		var xText, yText string
+		if err := rows.Scan(&secret, &xText, &yText); err != nil {
+			return nil, err
+		}
+		switch {
+		case secret == len(allShares):
+			allShares = append(allShares, nil)
+		case secret != len(allShares)-1:
+			return nil, fmt.Errorf("%w: secret %d follows secret %d", ErrTruncatedShares, secret, len(allShares)-1)
+		}
+
+		x, okX := new(big.Int).SetString(xText, 10)
+		y, okY := new(big.Int).SetString(yText, 10)
+		if !okX || !okY {
+			return nil, fmt.Errorf("secret %d: %w: share (%q, %q)", secret, ErrInvalidShare, xText, yText)
+		}
+		allShares[secret] = append(allShares[secret], Point{X: x, Y: y})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return allShares, nil
+}
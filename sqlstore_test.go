@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// No SQLite driver is vendored, so these tests run against memDB, a
+// database/sql driver that understands exactly the statements sqlstore.go
+// issues. It checks the Go side: transactions, placeholders and scanning.
+
+// memRow is one share row keyed by (secret_index, share_index)
+type memRow struct {
+	key  [2]int64
+	x, y string
+}
+
+type memDB struct {
+	mu     sync.Mutex
+	tables map[string]map[[2]int64]memRow
+}
+
+func (db *memDB) Connect(context.Context) (driver.Conn, error) { return &memConn{db: db}, nil }
+func (db *memDB) Driver() driver.Driver                        { return memDriver{} }
+
+type memDriver struct{}
+
+func (memDriver) Open(string) (driver.Conn, error) { return nil, errors.New("use sql.OpenDB") }
+
+type memConn struct {
+	db       *memDB
+	snapshot map[string]map[[2]int64]memRow // tables at Begin, restored on Rollback
+}
+
+func (c *memConn) Prepare(query string) (driver.Stmt, error) { return &memStmt{c, query}, nil }
+func (c *memConn) Close() error                              { return nil }
+
+func (c *memConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.snapshot = make(map[string]map[[2]int64]memRow)
+	for name, table := range c.db.tables {
+		c.snapshot[name] = maps.Clone(table)
+	}
+	return c, nil
+}
+
+func (c *memConn) Commit() error { c.snapshot = nil; return nil }
+
+func (c *memConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.tables, c.snapshot = c.snapshot, nil
+	return nil
+}
+
+type memStmt struct {
+	conn  *memConn
+	query string
+}
+
+func (s *memStmt) Close() error  { return nil }
+func (s *memStmt) NumInput() int { return -1 }
+
+// table returns the table named after keyword in the statement
+func (s *memStmt) table(keyword string) (string, map[[2]int64]memRow, error) {
+	fields := strings.Fields(s.query)
+	i := slices.Index(fields, keyword)
+	if i < 0 || i+1 >= len(fields) {
+		return "", nil, fmt.Errorf("memDB: cannot parse %q", s.query)
+	}
+	name := fields[i+1]
+	table, ok := s.conn.db.tables[name]
+	if !ok && !strings.HasPrefix(s.query, "CREATE") {
+		return "", nil, fmt.Errorf("memDB: no such table: %s", name)
+	}
+	return name, table, nil
+}
+
+func (s *memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.db.mu.Lock()
+	defer s.conn.db.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS"):
+		name, table, err := s.table("EXISTS")
+		if err == nil && table == nil {
+			s.conn.db.tables[name] = make(map[[2]int64]memRow)
+		}
+		return driver.RowsAffected(0), err
+	case strings.HasPrefix(s.query, "DELETE FROM"):
+		name, _, err := s.table("FROM")
+		if err == nil {
+			s.conn.db.tables[name] = make(map[[2]int64]memRow)
+		}
+		return driver.RowsAffected(0), err
+	case strings.HasPrefix(s.query, "INSERT INTO"):
+		_, table, err := s.table("INTO")
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != 4 {
+			return nil, fmt.Errorf("memDB: insert with %d arguments", len(args))
+		}
+		row := memRow{key: [2]int64{args[0].(int64), args[1].(int64)}, x: args[2].(string), y: args[3].(string)}
+		if _, ok := table[row.key]; ok {
+			return nil, fmt.Errorf("memDB: duplicate key %v", row.key)
+		}
+		table[row.key] = row
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("memDB: unsupported statement %q", s.query)
+}
+
+func (s *memStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.conn.db.mu.Lock()
+	defer s.conn.db.mu.Unlock()
+	if !strings.HasPrefix(s.query, "SELECT secret_index, x, y FROM") {
+		return nil, fmt.Errorf("memDB: unsupported query %q", s.query)
+	}
+	_, table, err := s.table("FROM")
+	if err != nil {
+		return nil, err
+	}
+	rows := slices.SortedFunc(maps.Values(table), func(a, b memRow) int {
+		return slices.Compare(a.key[:], b.key[:])
+	})
+	return &memRows{rows: rows}, nil
+}
+
+type memRows struct {
+	rows []memRow
+}
+
+func (r *memRows) Columns() []string { return []string{"secret_index", "x", "y"} }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	dest[0], dest[1], dest[2] = row.key[0], row.x, row.y
+	return nil
+}
+
+// openMemDB returns an empty in-memory database
+func openMemDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(&memDB{tables: make(map[string]map[[2]int64]memRow)})
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSharesDBRoundTrip(t *testing.T) {
+	db := openMemDB(t)
+	sss := newTestSharing(t, 3, 5)
+	if err := sss.SetPrime(Prime127); err != nil {
+		t.Fatal(err)
+	}
+	allShares := mustShareText(t, sss, "stored in a table")
+
+	if err := SaveSharesToDB(db, "vault_shares", allShares); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSharesFromDB(db, "vault_shares")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(allShares) {
+		t.Fatalf("loaded %d secrets, want %d", len(loaded), len(allShares))
+	}
+	for i := range allShares {
+		for j, p := range allShares[i] {
+			if loaded[i][j].X.Cmp(p.X) != 0 || loaded[i][j].Y.Cmp(p.Y) != 0 {
+				t.Fatalf("secret %d share %d: got %v, want %v", i, j, loaded[i][j], p)
+			}
+		}
+	}
+	if text, err := sss.ReconstructText(subsetShares(loaded, 4, 1, 2)); err != nil || text != "stored in a table" {
+		t.Fatalf("got %q, %v", text, err)
+	}
+
+	// Saving again replaces the previous rows
+	if err := SaveSharesToDB(db, "vault_shares", mustShareText(t, sss, "x")); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err = LoadSharesFromDB(db, "vault_shares"); err != nil || len(loaded) != 1 {
+		t.Fatalf("after resave: %d secrets, %v; want 1", len(loaded), err)
+	}
+
+	// A bad share rolls the whole save back
+	bad := [][]Point{{{X: big.NewInt(1), Y: big.NewInt(2)}, {X: big.NewInt(2)}}}
+	if err := SaveSharesToDB(db, "vault_shares", bad); !errors.Is(err, ErrInvalidShare) {
+		t.Fatalf("missing coordinate: got %v, want ErrInvalidShare", err)
+	}
+	if loaded, err = LoadSharesFromDB(db, "vault_shares"); err != nil || len(loaded) != 1 || len(loaded[0]) != 5 {
+		t.Fatalf("after failed save: %v, %v; want the previous 1 secret of 5 shares", loaded, err)
+	}
+}
+
+func TestSharesDBErrors(t *testing.T) {
+	for _, name := range []string{"", "1shares", "shares; DROP TABLE users", "sh-ares", "shares\"x"} {
+		if err := SaveSharesToDB(nil, name, nil); !errors.Is(err, ErrInvalidTableName) {
+			t.Errorf("save to %q: got %v, want ErrInvalidTableName", name, err)
+		}
+		if _, err := LoadSharesFromDB(nil, name); !errors.Is(err, ErrInvalidTableName) {
+			t.Errorf("load from %q: got %v, want ErrInvalidTableName", name, err)
+		}
+	}
+
+	db := openMemDB(t)
+	if _, err := LoadSharesFromDB(db, "missing"); err == nil {
+		t.Error("loaded from a table that does not exist")
+	}
+
+	// Secret 1 is missing, so secret 2 would shift into its place
+	if err := SaveSharesToDB(db, "gappy", [][]Point{{{X: big.NewInt(1), Y: big.NewInt(5)}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO gappy (secret_index, share_index, x, y) VALUES (?, ?, ?, ?)`, 2, 0, "1", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSharesFromDB(db, "gappy"); !errors.Is(err, ErrTruncatedShares) {
+		t.Errorf("gap in secret indices: got %v, want ErrTruncatedShares", err)
+	}
+
+	if _, err := db.Exec(`INSERT INTO gappy (secret_index, share_index, x, y) VALUES (?, ?, ?, ?)`, 1, 0, "1", "seven"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSharesFromDB(db, "gappy"); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("non-numeric y: got %v, want ErrInvalidShare", err)
+	}
+}
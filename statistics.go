@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Upper 1% point of the standard normal distribution, for a 0.01 significance level
+const chiSquaredZ = 2.3263478740408408
+
+// Most buckets used by IsStatisticallyRandom; fewer are used for small samples
+const maxRandomnessBins = 100
+
+// IsStatisticallyRandom checks that a single share reveals nothing about the
+// secret. For the x-coordinate of shares[0] it shares the same secret
+// iterations times, buckets the resulting y values and runs a chi-squared
+// test against the uniform distribution on [0, prime) at the 0.01 level.
+// Holding the secret fixed means any leak shows up as a skewed distribution.
+func (sss *ShamirSecretSharing) IsStatisticallyRandom(shares []Point, iterations int) (float64, bool, error) {
+	if len(shares) == 0 {
+		return 0, false, errors.New("at least one share is needed to pick the x-coordinate")
+	}
+	bins := min(maxRandomnessBins, iterations/5) // at least five expected per bin
+	if bins < 2 {
+		return 0, false, fmt.Errorf("need at least 10 iterations, got %d", iterations)
+	}
+
+	x := shares[0].X
+	counts := make([]int, bins)
+	binIndex := new(big.Int)
+	secret := big.NewInt(0)
+
+	for i := 0; i < iterations; i++ {
+		var y *big.Int
+		for _, share := range sss.GenerateShares(secret) {
+			if share.X.Cmp(x) == 0 {
+				y = share.Y
+			}
+		}
+		if y == nil {
+			return 0, false, fmt.Errorf("x = %s is not a share coordinate of this scheme", x)
+		}
+
+		// Full-quorum masks can exceed the prime slightly; fold them into the top bin
+		binIndex.Mul(y, big.NewInt(int64(bins)))
+		binIndex.Quo(binIndex, sss.prime)
+		counts[min(int(binIndex.Int64()), bins-1)]++
+	}
+
+	expected := float64(iterations) / float64(bins)
+	stat := 0.0
+	for _, observed := range counts {
+		d := float64(observed) - expected
+		stat += d * d / expected
+	}
+
+	return stat, stat <= chiSquaredCritical(bins-1), nil
+}
+
+// chiSquaredCritical approximates the upper critical value of the
+// chi-squared distribution using the Wilson–Hilferty transformation
+func chiSquaredCritical(df int) float64 {
+	k := float64(df)
+	t := 1 - 2/(9*k) + chiSquaredZ*math.Sqrt(2/(9*k))
+	return k * t * t * t
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestIsStatisticallyRandom(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	shares := sss.GenerateShares(big.NewInt(0))
+
+	// A sound scheme fails at the 0.01 level 1% of the time, so allow one
+	// retry to keep the false failure rate at 1 in 10,000
+	var stat float64
+	for range 2 {
+		var pass bool
+		var err error
+		stat, pass, err = sss.IsStatisticallyRandom(shares[2:], 10000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pass {
+			return
+		}
+	}
+	t.Fatalf("chi-squared statistic %.1f exceeds the critical value %.1f twice", stat, chiSquaredCritical(99))
+}
+
+func TestIsStatisticallyRandomDetectsLeak(t *testing.T) {
+	// With threshold 1 every share is the secret itself
+	sss := newTestSharing(t, 1, 3)
+	if stat, pass, err := sss.IsStatisticallyRandom(sss.GenerateShares(big.NewInt(0)), 1000); err != nil || pass {
+		t.Fatalf("1-of-3 shares: statistic %.1f, pass %v, %v; want a failure", stat, pass, err)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		shares     []Point
+		iterations int
+	}{
+		{"no shares", nil, 100},
+		{"foreign x", []Point{{X: big.NewInt(9), Y: big.NewInt(0)}}, 100},
+		{"too few iterations", sss.GenerateShares(big.NewInt(0)), 9},
+	} {
+		if _, _, err := sss.IsStatisticallyRandom(tc.shares, tc.iterations); err == nil {
+			t.Errorf("%s: no error", tc.name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+//go:build !production
+
+package main
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/rand/v2"
+)
+
+// GenerateTestShares is GenerateShares with its randomness drawn from a
+// generator seeded with seed, so tests get the same shares on every run and
+// never depend on the system's entropy source.
+//
+// Never call this in production code: anyone who knows or guesses the seed
+// can recompute every share and the secret. The function is left out of
+// builds tagged production (go build -tags production), so any production
+// caller fails to compile.
+func (sss *ShamirSecretSharing) GenerateTestShares(secret *big.Int, seed int64) []Point {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], uint64(seed))
+	rng := rand.NewChaCha8(key)
+
+	shares := make([]Point, sss.numShares)
+	if sss.usesXOR() {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(sss.prime.BitLen()))
+		last := new(big.Int).Set(secret)
+		for i := 0; i < sss.numShares-1; i++ {
+			mask := seededInt(rng, limit)
+			last.Xor(last, mask)
+			shares[i] = Point{X: big.NewInt(int64(sss.xOffset + i + 1)), Y: mask}
+		}
+		shares[sss.numShares-1] = Point{X: big.NewInt(int64(sss.xOffset + sss.numShares)), Y: last}
+		return shares
+	}
+
+	coefficients := make([]*big.Int, sss.threshold)
+	coefficients[0] = new(big.Int).Set(secret)
+	for i := 1; i < sss.threshold; i++ {
+		coefficients[i] = seededInt(rng, sss.prime)
+	}
+	for i := range shares {
+		x := big.NewInt(int64(sss.xOffset + i + 1))
+		shares[i] = Point{X: x, Y: evaluateCoefficients(coefficients, x, sss.prime)}
+	}
+	return shares
+}
+
+// seededInt draws a uniform value in [0, limit) from rng by rejection
+func seededInt(rng *rand.ChaCha8, limit *big.Int) *big.Int {
+	bits := new(big.Int).Sub(limit, big.NewInt(1)).BitLen()
+	n := new(big.Int)
+	if bits == 0 {
+		return n
+	}
+	buf := make([]byte, (bits+7)/8)
+	for {
+		rng.Read(buf)
+		// Clear the bits above the limit's width so few draws are rejected
+		buf[0] &= byte(0xff >> (8*len(buf) - bits))
+		if n.SetBytes(buf).Cmp(limit) < 0 {
+			return n
+		}
+	}
+}
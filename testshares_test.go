@@ -0,0 +1,57 @@
+//go:build !production
+
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGenerateTestSharesReproducible(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	secret := big.NewInt(77777)
+
+	first := sss.GenerateTestShares(secret, 42)
+	again := sss.GenerateTestShares(secret, 42)
+	other := sss.GenerateTestShares(secret, 43)
+	same, differs := true, false
+	for i := range first {
+		same = same && first[i].X.Cmp(again[i].X) == 0 && first[i].Y.Cmp(again[i].Y) == 0
+		differs = differs || first[i].Y.Cmp(other[i].Y) != 0
+	}
+	if !same {
+		t.Fatalf("seed 42 gave %v then %v", first, again)
+	}
+	if !differs {
+		t.Fatal("seeds 42 and 43 gave the same shares")
+	}
+
+	for _, subset := range [][]Point{first[:3], first[2:], {first[4], first[0], first[3]}} {
+		if got := sss.ReconstructSecret(subset); got.Cmp(secret) != 0 {
+			t.Fatalf("reconstructed %s, want %s", got, secret)
+		}
+	}
+
+	// Coefficients stay below a large prime too
+	large := newTestSharing(t, 2, 3)
+	if err := large.SetPrime(Prime521); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range large.GenerateTestShares(secret, 7) {
+		if !p.IsValid(Prime521) {
+			t.Fatalf("share %v is not reduced mod Prime521", p)
+		}
+	}
+
+	xor := newTestSharing(t, 3, 3)
+	if err := xor.SetScheme(SchemeXOR); err != nil {
+		t.Fatal(err)
+	}
+	xorShares := xor.GenerateTestShares(secret, 42)
+	if got := xor.ReconstructSecret(xorShares); got.Cmp(secret) != 0 {
+		t.Fatalf("XOR shares reconstruct to %s, want %s", got, secret)
+	}
+	if got := xor.GenerateTestShares(secret, 42); got[0].Y.Cmp(xorShares[0].Y) != 0 {
+		t.Fatal("XOR shares from the same seed differ")
+	}
+}
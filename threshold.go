@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ParseThreshold interprets a threshold given as an absolute count ("3"), a
+// percentage of numShares rounded up ("60%"), or "majority" (more than half)
+func ParseThreshold(spec string, numShares int) (int, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+
+	var threshold int
+	switch {
+	case spec == "majority":
+		threshold = numShares/2 + 1
+	case strings.HasSuffix(spec, "%"):
+		// Exact rational arithmetic so 60% of 5 is exactly 3, not 3.0000000000000004
+		percent, ok := new(big.Rat).SetString(strings.TrimSuffix(spec, "%"))
+		if !ok || percent.Sign() <= 0 || percent.Cmp(big.NewRat(100, 1)) > 0 {
+			return 0, fmt.Errorf("%w: invalid percentage %q", ErrInvalidThreshold, spec)
+		}
+		share := new(big.Rat).Mul(percent, big.NewRat(int64(numShares), 100))
+		ceil := new(big.Int).Add(share.Num(), new(big.Int).Sub(share.Denom(), big.NewInt(1)))
+		threshold = int(ceil.Quo(ceil, share.Denom()).Int64())
+	default:
+		n, err := strconv.Atoi(spec)
+		if err != nil {
+			return 0, fmt.Errorf("%w: expected a number, a percentage or \"majority\", got %q", ErrInvalidThreshold, spec)
+		}
+		threshold = n
+	}
+
+	if threshold < 1 || threshold > numShares {
+		return 0, fmt.Errorf("%w: %q gives %d of %d shares", ErrInvalidThreshold, spec, threshold, numShares)
+	}
+	return threshold, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		spec      string
+		numShares int
+		want      int
+	}{
+		{"3", 5, 3},
+		{" 5 ", 5, 5},
+		{"majority", 5, 3},
+		{"Majority", 4, 3},
+		{"majority", 1, 1},
+		{"50%", 5, 3}, // 2.5 rounds up
+		{"50%", 4, 2},
+		{"60%", 5, 3}, // exactly 3, not rounded past it
+		{"61%", 5, 4},
+		{"1%", 5, 1},
+		{"100%", 7, 7},
+		{"33.4%", 3, 2},
+	} {
+		got, err := ParseThreshold(tc.spec, tc.numShares)
+		if err != nil || got != tc.want {
+			t.Errorf("ParseThreshold(%q, %d) = %d, %v; want %d", tc.spec, tc.numShares, got, err, tc.want)
+		}
+	}
+}
+
+func TestParseThresholdInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		spec      string
+		numShares int
+	}{
+		{"0", 5},
+		{"6", 5},
+		{"-1", 5},
+		{"0%", 5},
+		{"101%", 5},
+		{"%", 5},
+		{"half", 5},
+		{"", 5},
+	} {
+		if got, err := ParseThreshold(tc.spec, tc.numShares); !errors.Is(err, ErrInvalidThreshold) {
+			t.Errorf("ParseThreshold(%q, %d) = %d, %v; want ErrInvalidThreshold", tc.spec, tc.numShares, got, err)
+		}
+	}
+}
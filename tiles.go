@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// TileShares holds the pixel shares of one tile
+type TileShares struct {
+	Row, Col int
+	Shares   [][]Point
+}
+
+// TileLayout records how an image was divided into tiles. Tiles on the right
+// and bottom edges may be smaller than TileWidth x TileHeight.
+type TileLayout struct {
+	Width, Height         int
+	TileWidth, TileHeight int
+	Rows, Cols            int
+}
+
+// tileBounds returns the pixel rectangle covered by a tile
+func (l TileLayout) tileBounds(row, col int) image.Rectangle {
+	r := image.Rect(col*l.TileWidth, row*l.TileHeight, (col+1)*l.TileWidth, (row+1)*l.TileHeight)
+	return r.Intersect(image.Rect(0, 0, l.Width, l.Height))
+}
+
+// ShareImageTile splits an image into tiles and shares each independently,
+// so only one tile's shares need to be handled at a time
+func (sss *ShamirSecretSharing) ShareImageTile(imagePath string, tileWidth, tileHeight int) ([]TileShares, TileLayout, error) {
+	if tileWidth < 1 || tileHeight < 1 {
+		return nil, TileLayout{}, errors.New("tile dimensions must be positive")
+	}
+
+	img, err := decodeImageFile(imagePath)
+	if err != nil {
+		return nil, TileLayout{}, err
+	}
+	sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, TileLayout{}, fmt.Errorf("image type %T cannot be tiled", img)
+	}
+
+	bounds := img.Bounds()
+	layout := TileLayout{
+		Width:      bounds.Dx(),
+		Height:     bounds.Dy(),
+		TileWidth:  tileWidth,
+		TileHeight: tileHeight,
+		Rows:       (bounds.Dy() + tileHeight - 1) / tileHeight,
+		Cols:       (bounds.Dx() + tileWidth - 1) / tileWidth,
+	}
+
+	tiles := make([]TileShares, 0, layout.Rows*layout.Cols)
+	for row := 0; row < layout.Rows; row++ {
+		for col := 0; col < layout.Cols; col++ {
+			r := layout.tileBounds(row, col).Add(bounds.Min)
+			pixels, _, _ := grayPixels(sub.SubImage(r), sss.conversion)
+			tiles = append(tiles, TileShares{Row: row, Col: col, Shares: sss.sharePixels(pixels)})
+		}
+	}
+
+	return tiles, layout, nil
+}
+
+// ReconstructImageTile stitches tiles from ShareImageTile back into a PNG
+func (sss *ShamirSecretSharing) ReconstructImageTile(tiles []TileShares, layout TileLayout, outputPath string) error {
+	if len(tiles) != layout.Rows*layout.Cols {
+		return fmt.Errorf("have %d tiles, layout needs %d", len(tiles), layout.Rows*layout.Cols)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, layout.Width, layout.Height))
+	seen := make(map[[2]int]bool, len(tiles))
+
+	for _, tile := range tiles {
+		if tile.Row < 0 || tile.Row >= layout.Rows || tile.Col < 0 || tile.Col >= layout.Cols {
+			return fmt.Errorf("tile (%d, %d) is outside the %dx%d grid", tile.Row, tile.Col, layout.Rows, layout.Cols)
+		}
+		key := [2]int{tile.Row, tile.Col}
+		if seen[key] {
+			return fmt.Errorf("duplicate tile (%d, %d)", tile.Row, tile.Col)
+		}
+		seen[key] = true
+
+		r := layout.tileBounds(tile.Row, tile.Col)
+		pixels, err := sss.ReconstructImageBytes(tile.Shares, r.Dx(), r.Dy())
+		if err != nil {
+			return fmt.Errorf("tile (%d, %d): %w", tile.Row, tile.Col, err)
+		}
+		for y := 0; y < r.Dy(); y++ {
+			copy(img.Pix[img.PixOffset(r.Min.X, r.Min.Y+y):], pixels[y*r.Dx():(y+1)*r.Dx()])
+		}
+	}
+
+	return writePNGFile(img, outputPath)
+}
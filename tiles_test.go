@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tileTestImage returns a grayscale image with a distinct value per pixel
+func tileTestImage(width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i * 31)
+	}
+	return img
+}
+
+func TestImageTileRoundTrip(t *testing.T) {
+	for _, size := range []image.Point{{32, 32}, {30, 20}} {
+		src := tileTestImage(size.X, size.Y)
+		sss := newTestSharing(t, 2, 3)
+		tiles, layout, err := sss.ShareImageTile(writeTestPNG(t, src), 8, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantRows, wantCols := (size.Y+7)/8, (size.X+7)/8
+		if len(tiles) != wantRows*wantCols || layout.Rows != wantRows || layout.Cols != wantCols {
+			t.Fatalf("%v: got %d tiles in a %dx%d grid, want %dx%d", size, len(tiles), layout.Rows, layout.Cols, wantRows, wantCols)
+		}
+
+		// Tiles may arrive in any order
+		tiles[0], tiles[len(tiles)-1] = tiles[len(tiles)-1], tiles[0]
+		outPath := filepath.Join(t.TempDir(), "stitched.png")
+		if err := sss.ReconstructImageTile(tiles, layout, outPath); err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.Open(outPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := png.Decode(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gray, ok := got.(*image.Gray); !ok || !bytes.Equal(gray.Pix, src.Pix) {
+			t.Fatalf("%v: stitched image differs from the original", size)
+		}
+	}
+}
+
+func TestReconstructImageTileErrors(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	tiles, layout, err := sss.ShareImageTile(writeTestPNG(t, tileTestImage(16, 16)), 8, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(t.TempDir(), "out.png")
+
+	if err := sss.ReconstructImageTile(tiles[:3], layout, outPath); err == nil {
+		t.Error("three of four tiles accepted")
+	}
+	repeated := append([]TileShares{tiles[0]}, tiles[:3]...)
+	if err := sss.ReconstructImageTile(repeated, layout, outPath); err == nil {
+		t.Error("a repeated tile accepted")
+	}
+	outside := append([]TileShares(nil), tiles...)
+	outside[3].Row = 2
+	if err := sss.ReconstructImageTile(outside, layout, outPath); err == nil {
+		t.Error("a tile outside the grid accepted")
+	}
+	if _, _, err := sss.ShareImageTile(writeTestPNG(t, tileTestImage(4, 4)), 0, 8); err == nil {
+		t.Error("zero tile width accepted")
+	}
+}
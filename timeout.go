@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"math/big"
+	"time"
+)
+
+// ErrTimeout is returned when reconstruction does not finish in time
+var ErrTimeout = errors.New("reconstruction timed out")
+
+// timeoutError converts a context expiry into ErrTimeout
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+	return err
+}
+
+// ReconstructSecretContext reconstructs a secret like
+// ReconstructSecretVerbose, stopping with ctx.Err() once ctx is done. The
+// interpolation runs in the caller's goroutine and checks ctx as it goes, so
+// nothing is left running after it returns.
+func (sss *ShamirSecretSharing) ReconstructSecretContext(ctx context.Context, shares []Point) (*big.Int, error) {
+	secret, _, err := sss.reconstructSecretVerbose(ctx, shares)
+	return secret, err
+}
+
+// ReconstructSecretWithTimeout reconstructs a secret, stopping with
+// ErrTimeout once timeout has elapsed
+func (sss *ShamirSecretSharing) ReconstructSecretWithTimeout(shares []Point, timeout time.Duration) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	secret, err := sss.ReconstructSecretContext(ctx, shares)
+	if err != nil {
+		return nil, timeoutError(err, timeout)
+	}
+	return secret, nil
+}
+
+// ReconstructTextWithTimeout reconstructs text, stopping with ErrTimeout once
+// timeout has elapsed
+func (sss *ShamirSecretSharing) ReconstructTextWithTimeout(allShares [][]Point, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	bytes, err := sss.reconstructArbitraryBytes(ctx, allShares)
+	if err != nil {
+		return "", timeoutError(err, timeout)
+	}
+	return string(bytes), nil
+}
+
+// ReconstructImageWithTimeout reconstructs an image, stopping with
+// ErrTimeout once timeout has elapsed
+func (sss *ShamirSecretSharing) ReconstructImageWithTimeout(allShares [][]Point, width, height int, timeout time.Duration) (image.Image, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	img, err := sss.reconstructImage(ctx, allShares, width, height)
+	if err != nil {
+		return nil, timeoutError(err, timeout)
+	}
+	return img, nil
+}
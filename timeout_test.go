@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// slowShares returns n arbitrary points for an n-of-n instance. Lagrange
+// interpolation is superlinear in the threshold, so a large n makes a share
+// set that is cheap to build but slow to reconstruct.
+func slowShares(t *testing.T, n int) (*ShamirSecretSharing, []Point) {
+	t.Helper()
+	sss := newTestSharing(t, n, n)
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{X: big.NewInt(int64(i + 1)), Y: big.NewInt(int64(i*7919) % PRIME.Int64())}
+	}
+	return sss, points
+}
+
+func TestReconstructSecretWithTimeout(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	secret := big.NewInt(99)
+	got, err := sss.ReconstructSecretWithTimeout(sss.GenerateShares(secret), time.Second)
+	if err != nil || got.Cmp(secret) != 0 {
+		t.Fatalf("fast reconstruction: got %v, %v; want %s", got, err, secret)
+	}
+
+	slow, points := slowShares(t, 1200)
+	start := time.Now()
+	if _, err := slow.ReconstructSecretWithTimeout(points, 2*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("slow reconstruction: got %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("timeout returned after %v", elapsed)
+	}
+}
+
+func TestReconstructTextWithTimeout(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if text, err := sss.ReconstructTextWithTimeout(mustShareText(t, sss, "quick"), time.Second); err != nil || text != "quick" {
+		t.Fatalf("fast reconstruction: got %q, %v", text, err)
+	}
+
+	slow, points := slowShares(t, 400)
+	allShares := make([][]Point, 20)
+	for i := range allShares {
+		allShares[i] = points
+	}
+	if _, err := slow.ReconstructTextWithTimeout(allShares, 5*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("slow reconstruction: got %v, want ErrTimeout", err)
+	}
+}
+
+func TestReconstructImageWithTimeout(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	allShares := sss.sharePixels(make([]uint8, 10*10))
+	if _, err := sss.ReconstructImageWithTimeout(allShares, 10, 10, time.Second); err != nil {
+		t.Fatalf("fast reconstruction: %v", err)
+	}
+
+	// A progress callback that blocks past the deadline stands in for a slow
+	// reconstruction
+	sss.SetProgress(func(done, total int) { time.Sleep(10 * time.Millisecond) })
+	img, err := sss.ReconstructImageWithTimeout(allShares, 10, 10, 5*time.Millisecond)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("slow reconstruction: got %v, want ErrTimeout", err)
+	}
+	if img != nil {
+		t.Fatalf("got an image alongside ErrTimeout")
+	}
+}
+
+// countdownContext reports cancellation from the nth call to Err onwards and
+// counts every call, so a test can see exactly when work stopped
+type countdownContext struct {
+	context.Context
+	n, calls int
+}
+
+func (c *countdownContext) Err() error {
+	c.calls++
+	if c.calls >= c.n {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestReconstructSecretContextStops(t *testing.T) {
+	for _, method := range []InterpolationMethod{InterpolationLagrange, InterpolationNewton} {
+		slow, points := slowShares(t, 300)
+		slow.SetInterpolation(method)
+
+		// Cancelled partway through, the interpolation returns at the next
+		// check instead of running on to the end
+		ctx := &countdownContext{Context: context.Background(), n: 5}
+		if _, err := slow.ReconstructSecretContext(ctx, points); !errors.Is(err, context.Canceled) {
+			t.Fatalf("method %d: got %v, want context.Canceled", method, err)
+		}
+		if ctx.calls != 5 {
+			t.Fatalf("method %d: ctx checked %d times after cancellation at 5, want 5", method, ctx.calls)
+		}
+
+		// Left alone, it checks once per point or pass
+		ctx = &countdownContext{Context: context.Background(), n: 1 << 30}
+		if _, err := slow.ReconstructSecretContext(ctx, points); err != nil {
+			t.Fatalf("method %d: %v", method, err)
+		}
+		if ctx.calls < 299 {
+			t.Fatalf("method %d: ctx checked only %d times for 300 points", method, ctx.calls)
+		}
+	}
+
+	// The timeout wrapper starts no goroutine that could outlive it
+	slow, points := slowShares(t, 1200)
+	before := runtime.NumGoroutine()
+	if _, err := slow.ReconstructSecretWithTimeout(points, time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("got %v, want ErrTimeout", err)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("%d goroutines after timing out, %d before", after, before)
+	}
+}
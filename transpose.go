@@ -0,0 +1,66 @@
+package main
+
+import (
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+)
+
+// ErrRaggedShares is returned when share rows have different lengths
+var ErrRaggedShares = errors.New("shares are not rectangular")
+
+// transposeShares swaps the two indices of a rectangular share matrix
+func transposeShares(rows [][]Point) ([][]Point, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	width := len(rows[0])
+	for i, row := range rows {
+		if len(row) != width {
+			return nil, fmt.Errorf("%w: row %d has %d shares, expected %d", ErrRaggedShares, i, len(row), width)
+		}
+	}
+
+	columns := make([][]Point, width)
+	for j := range columns {
+		columns[j] = make([]Point, len(rows))
+		for i, row := range rows {
+			columns[j][i] = row[j]
+		}
+	}
+	return columns, nil
+}
+
+// Transpose converts secret-major shares ([secret][participant]) into
+// participant-major shares ([participant][secret])
+func Transpose(allShares [][]Point) ([][]Point, error) {
+	return transposeShares(allShares)
+}
+
+// Untranspose converts participant-major shares back to secret-major
+func Untranspose(byParticipant [][]Point) ([][]Point, error) {
+	return transposeShares(byParticipant)
+}
+
+// ShuffleShares returns a copy of secret-major shares with each secret's
+// shares in random order, so an audit file does not reveal which line belongs
+// to which participant's position. Reconstruction reads x from each point and
+// is unaffected, but the result must not be passed to Transpose.
+func ShuffleShares(allShares [][]Point) ([][]Point, error) {
+	var seed [32]byte
+	if _, err := cryptorand.Read(seed[:]); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewChaCha8(seed))
+
+	shuffled := make([][]Point, len(allShares))
+	for i, shares := range allShares {
+		shuffled[i] = append([]Point(nil), shares...)
+		rng.Shuffle(len(shuffled[i]), func(a, b int) {
+			shuffled[i][a], shuffled[i][b] = shuffled[i][b], shuffled[i][a]
+		})
+	}
+	return shuffled, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+)
+
+// testMatrix builds a rows x cols share matrix with X = row and Y = col
+func testMatrix(rows, cols int) [][]Point {
+	m := make([][]Point, rows)
+	for i := range m {
+		m[i] = make([]Point, cols)
+		for j := range m[i] {
+			m[i][j] = Point{X: big.NewInt(int64(i)), Y: big.NewInt(int64(j))}
+		}
+	}
+	return m
+}
+
+func TestTransposeRoundTrip(t *testing.T) {
+	allShares := testMatrix(3, 4)
+	byParticipant, err := Transpose(allShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byParticipant) != 4 {
+		t.Fatalf("got %d participants, want 4", len(byParticipant))
+	}
+	for j, column := range byParticipant {
+		if len(column) != 3 {
+			t.Fatalf("participant %d has %d shares, want 3", j, len(column))
+		}
+		for i, p := range column {
+			if p.X.Int64() != int64(i) || p.Y.Int64() != int64(j) {
+				t.Fatalf("byParticipant[%d][%d] = %v", j, i, p)
+			}
+		}
+	}
+
+	back, err := Untranspose(byParticipant)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range allShares {
+		for j := range allShares[i] {
+			if back[i][j] != allShares[i][j] {
+				t.Fatalf("round trip moved share [%d][%d]", i, j)
+			}
+		}
+	}
+
+	if got, err := Transpose(nil); err != nil || got != nil {
+		t.Fatalf("Transpose(nil) = %v, %v", got, err)
+	}
+}
+
+func TestTransposeRagged(t *testing.T) {
+	ragged := testMatrix(3, 4)
+	ragged[2] = ragged[2][:3]
+	if _, err := Transpose(ragged); !errors.Is(err, ErrRaggedShares) {
+		t.Fatalf("Transpose: got %v, want ErrRaggedShares", err)
+	}
+	if _, err := Untranspose(ragged); !errors.Is(err, ErrRaggedShares) {
+		t.Fatalf("Untranspose: got %v, want ErrRaggedShares", err)
+	}
+}
+
+func TestShuffleShares(t *testing.T) {
+	sss := newTestSharing(t, 3, 5)
+	text := "the audit file must not reveal x order"
+	allShares := mustShareText(t, sss, text)
+	shuffled, err := ShuffleShares(allShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "audit.txt")
+	if err := saveTextShares(shuffled, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadTextShares(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every secret keeps its own shares; the chance that all of them also
+	// keep x = 1..5 order is 120^-38
+	moved := false
+	for i, shares := range loaded {
+		for j, p := range shares {
+			if allShares[i][j].X.Int64() != int64(j+1) {
+				t.Fatal("ShuffleShares reordered its input")
+			}
+			moved = moved || p.X.Int64() != int64(j+1)
+			if want := allShares[i][p.X.Int64()-1]; p.Y.Cmp(want.Y) != 0 {
+				t.Fatalf("secret %d: share x = %s has the wrong y", i, p.X)
+			}
+		}
+	}
+	if !moved {
+		t.Fatal("every secret kept its shares in x order")
+	}
+	if got, err := sss.ReconstructText(loaded); err != nil || got != text {
+		t.Fatalf("got %q, %v; want %q", got, err, text)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ShareFileError describes where a share file failed validation
+type ShareFileError struct {
+	Line  int // 1-based line number in the file
+	Index int // character or pixel index, or -1 for the header
+	Err   error
+}
+
+func (e *ShareFileError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d (index %d): %v", e.Line, e.Index, e.Err)
+}
+
+func (e *ShareFileError) Unwrap() error {
+	return e.Err
+}
+
+// shareFileValidator walks a share file line by line, tracking position
+type shareFileValidator struct {
+	scanner *bufio.Scanner
+	line    int
+	limit   *big.Int // y values must be below it
+}
+
+// next returns the next non-header line
+func (v *shareFileValidator) next() (string, bool) {
+	for v.scanner.Scan() {
+		v.line++
+		text := v.scanner.Text()
+		if !strings.HasPrefix(text, "#") {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// fail builds a ShareFileError at the current line
+func (v *shareFileValidator) fail(index int, format string, args ...any) error {
+	return &ShareFileError{Line: v.line, Index: index, Err: fmt.Errorf(format, args...)}
+}
+
+// truncated reports a file that ended before the expected data
+func (v *shareFileValidator) truncated(index int, what string) error {
+	return &ShareFileError{Line: v.line + 1, Index: index, Err: fmt.Errorf("%w: missing %s", ErrTruncatedShares, what)}
+}
+
+// checkSecrets validates count blocks of "<n>" followed by n "x y" lines
+func (v *shareFileValidator) checkSecrets(count int) error {
+	for i := 0; i < count; i++ {
+		text, ok := v.next()
+		if !ok {
+			return v.truncated(i, "share count")
+		}
+		numShares, err := strconv.Atoi(text)
+		if err != nil || numShares < 0 {
+			return v.fail(i, "invalid share count %q", text)
+		}
+
+		for j := 0; j < numShares; j++ {
+			text, ok := v.next()
+			if !ok {
+				return v.truncated(i, fmt.Sprintf("share %d of %d", j+1, numShares))
+			}
+
+			parts := strings.Split(text, " ")
+			if len(parts) != 2 {
+				return v.fail(i, "expected \"x y\", got %q", text)
+			}
+			x, ok := new(big.Int).SetString(parts[0], 10)
+			if !ok || x.Sign() <= 0 {
+				return v.fail(i, "x value %q is not a positive integer", parts[0])
+			}
+			y, ok := new(big.Int).SetString(parts[1], 10)
+			if !ok {
+				return v.fail(i, "y value %q is not an integer", parts[1])
+			}
+			if y.Sign() < 0 || y.Cmp(v.limit) >= 0 {
+				return v.fail(i, "y value %s is outside [0, %s)", y, v.limit)
+			}
+		}
+	}
+
+	// Anything after the declared data means the header and body disagree
+	if text, ok := v.next(); ok {
+		return v.fail(-1, "unexpected data after last share: %q", text)
+	}
+	if err := v.scanner.Err(); err != nil {
+		return &ShareFileError{Line: v.line, Index: -1, Err: err}
+	}
+	return nil
+}
+
+// shareValueLimit reads filename's header and returns the bound on its y
+// values: the prime it records, or fallback when it records none, and the
+// bit width of that prime for XOR shares
+func shareValueLimit(filename string, fallback *big.Int) (*big.Int, error) {
+	meta, err := readShareHeader(filename)
+	if err != nil && !errors.Is(err, ErrTruncatedShares) {
+		return nil, err
+	}
+	prime := fallback
+	if meta.Prime != nil {
+		prime = meta.Prime
+	}
+	if meta.Scheme == SchemeXOR {
+		return new(big.Int).Lsh(big.NewInt(1), uint(prime.BitLen())), nil
+	}
+	return prime, nil
+}
+
+// ValidateTextShareFile checks a text share file for corruption without
+// reconstructing. Values are checked against the prime in the file's header,
+// or against prime for files that do not record one.
+func ValidateTextShareFile(filename string, prime *big.Int) error {
+	limit, err := shareValueLimit(filename, prime)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	v := &shareFileValidator{scanner: newShareScanner(file), limit: limit}
+
+	text, ok := v.next()
+	if !ok {
+		return v.truncated(-1, "character count")
+	}
+	numChars, err := strconv.Atoi(text)
+	if err != nil || numChars < 0 {
+		return v.fail(-1, "invalid character count %q", text)
+	}
+
+	return v.checkSecrets(numChars)
+}
+
+// ValidateImageShareFile checks an image share file for corruption without
+// reconstructing, using the prime like ValidateTextShareFile
+func ValidateImageShareFile(filename string, prime *big.Int) error {
+	limit, err := shareValueLimit(filename, prime)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	v := &shareFileValidator{scanner: newShareScanner(file), limit: limit}
+
+	text, ok := v.next()
+	if !ok {
+		return v.truncated(-1, "image dimensions")
+	}
+	parts := strings.Split(text, " ")
+	if len(parts) != 3 {
+		return v.fail(-1, "expected \"width height pixels\", got %q", text)
+	}
+
+	var dims [3]int
+	for i, part := range parts {
+		dims[i], err = strconv.Atoi(part)
+		if err != nil || dims[i] < 0 {
+			return v.fail(-1, "invalid image header value %q", part)
+		}
+	}
+	width, height, numPixels := dims[0], dims[1], dims[2]
+	if numPixels != width*height {
+		return v.fail(-1, "%d pixels declared for a %dx%d image", numPixels, width, height)
+	}
+
+	return v.checkSecrets(numPixels)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateTextShareFileLargeField(t *testing.T) {
+	sss := newTestSharing(t, 2, 3)
+	if err := sss.SetPrime(Prime61); err != nil {
+		t.Fatal(err)
+	}
+	path := saveTestTextShares(t, sss, "wide field", ShareMetadata{}, t.TempDir(), "shares.txt")
+
+	if err := ValidateTextShareFile(path, Prime61); err != nil {
+		t.Fatalf("ValidateTextShareFile over Prime61: %v", err)
+	}
+	var fileErr *ShareFileError
+	if err := ValidateTextShareFile(path, Prime31); !errors.As(err, &fileErr) {
+		t.Fatalf("ValidateTextShareFile over the wrong field: got %v, want a *ShareFileError", err)
+	}
+}
+
+func TestValidateTextShareFileTruncated(t *testing.T) {
+	path := writeTestFile(t, "short.txt", "2\n2\n1 5\n2 9\n2\n1 6\n")
+	if err := ValidateTextShareFile(path, Prime31); !errors.Is(err, ErrTruncatedShares) {
+		t.Fatalf("got %v, want ErrTruncatedShares", err)
+	}
+	if err := ValidateTextShareFile(filepath.Join(t.TempDir(), "missing.txt"), Prime31); err == nil {
+		t.Fatal("missing file validated")
+	}
+}
+
+func TestValidateShareFileCorruption(t *testing.T) {
+	for name, tc := range map[string]struct {
+		content string
+		line    int
+		index   int
+	}{
+		"non-numeric y":        {"2\n2\n1 5\n2 9\n2\n1 6\nx y\n", 7, 1},
+		"y equal to the prime": {"1\n2\n1 5\n2 2147483647\n", 4, 0},
+		"negative y":           {"1\n2\n1 -5\n2 9\n", 3, 0},
+		"zero x":               {"1\n2\n0 5\n2 9\n", 3, 0},
+		"extra data":           {"1\n1\n1 5\n1\n", 4, -1},
+		"bad count":            {"one\n", 1, -1},
+	} {
+		err := ValidateTextShareFile(writeTestFile(t, "bad.txt", tc.content), Prime31)
+		var fileErr *ShareFileError
+		if !errors.As(err, &fileErr) {
+			t.Errorf("%s: got %v, want a *ShareFileError", name, err)
+			continue
+		}
+		if fileErr.Line != tc.line || fileErr.Index != tc.index {
+			t.Errorf("%s: reported line %d index %d, want line %d index %d", name, fileErr.Line, fileErr.Index, tc.line, tc.index)
+		}
+	}
+
+	if err := ValidateTextShareFile(writeTestFile(t, "good.txt", "#version 1\n1\n2\n1 5\n2 9\n"), Prime31); err != nil {
+		t.Errorf("valid file: %v", err)
+	}
+}
+
+func TestValidateImageShareFile(t *testing.T) {
+	good := "2 1 2\n2\n1 5\n2 9\n2\n1 6\n2 10\n"
+	if err := ValidateImageShareFile(writeTestFile(t, "good.img", good), Prime31); err != nil {
+		t.Fatalf("valid file: %v", err)
+	}
+	if err := ValidateImageShareFile(writeTestFile(t, "dims.img", "2 2 3\n"), Prime31); err == nil {
+		t.Error("pixel count that does not match the dimensions was accepted")
+	}
+	if err := ValidateImageShareFile(writeTestFile(t, "short.img", good[:len(good)-6]), Prime31); !errors.Is(err, ErrTruncatedShares) {
+		t.Errorf("truncated file: got %v, want ErrTruncatedShares", err)
+	}
+}
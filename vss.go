@@ -0,0 +1,222 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrShareCommitmentMismatch is returned when a share does not open its commitment
+var ErrShareCommitmentMismatch = errors.New("share does not match dealer commitment")
+
+// FeldmanVSS adds verifiability to a scheme: the dealer publishes a
+// commitment to the secret and to every share, and each holder checks their
+// share against it before relying on it. Every commitment is blinded by a
+// value from a second random polynomial of the same degree, handed to each
+// holder privately with their share, so with PedersenCommitment this is
+// Pedersen's VSS: the commitments reveal nothing about the shares or the
+// secret, and can still be checked against each other.
+type FeldmanVSS struct {
+	sss    *ShamirSecretSharing
+	scheme CommitmentScheme
+}
+
+// NewFeldmanVSS wraps sss, committing with scheme. The field must have at
+// least 256 bits (see Prime521) and sss must use polynomial shares. A
+// PedersenCommitment must be the group for sss's prime.
+func NewFeldmanVSS(sss *ShamirSecretSharing, scheme CommitmentScheme) (*FeldmanVSS, error) {
+	if err := checkCommitmentField(sss.prime); err != nil {
+		return nil, err
+	}
+	if sss.usesXOR() {
+		return nil, fmt.Errorf("%w: XOR shares cannot be verified", ErrInvalidThreshold)
+	}
+	if pc, ok := scheme.(*PedersenCommitment); ok && pc.Q.Cmp(sss.prime) != 0 {
+		return nil, fmt.Errorf("%w: commitment group has order %s, not the field prime", ErrInvalidPrime, pc.Q)
+	}
+	return &FeldmanVSS{sss: sss, scheme: scheme}, nil
+}
+
+// Deal shares a secret and returns the shares, their blinding values and the
+// commitments to publish. Holder i gets shares[i] and blindings[i] privately.
+// commitments[0] is for the secret and commitments[i] for shares[i-1].
+func (vss *FeldmanVSS) Deal(secret *big.Int) ([]Point, []*big.Int, []*big.Int, error) {
+	if secret.Sign() < 0 || secret.Cmp(vss.sss.prime) >= 0 {
+		return nil, nil, nil, ErrSecretOutOfRange
+	}
+	blinding, err := rand.Int(rand.Reader, vss.sss.prime)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	shares := vss.sss.GenerateShares(secret)
+	blindingShares := vss.sss.GenerateShares(blinding)
+
+	blindings := make([]*big.Int, len(shares))
+	commitments := make([]*big.Int, len(shares)+1)
+	commitments[0] = vss.scheme.Commit(secret, blinding)
+	for i, share := range shares {
+		blindings[i] = blindingShares[i].Y
+		commitments[i+1] = vss.scheme.Commit(share.Y, blindings[i])
+	}
+	return shares, blindings, commitments, nil
+}
+
+// commitmentIndex maps a share to its position in the commitment vector
+func (vss *FeldmanVSS) commitmentIndex(share Point, commitments []*big.Int) (int, bool) {
+	k := new(big.Int).Sub(share.X, big.NewInt(int64(vss.sss.xOffset)))
+	if !k.IsInt64() || k.Int64() < 1 || k.Int64() >= int64(len(commitments)) {
+		return 0, false
+	}
+	return int(k.Int64()), true
+}
+
+// VerifyShare checks a share and its blinding value against the dealer's
+// published commitments
+func (vss *FeldmanVSS) VerifyShare(share Point, blinding *big.Int, commitments []*big.Int) error {
+	if share.X == nil || share.Y == nil {
+		return fmt.Errorf("%w: missing coordinate", ErrShareCommitmentMismatch)
+	}
+	k, ok := vss.commitmentIndex(share, commitments)
+	if !ok {
+		return fmt.Errorf("%w: no commitment for x = %s", ErrShareCommitmentMismatch, share.X)
+	}
+	if !vss.scheme.Open(commitments[k], share.Y, blinding) {
+		return fmt.Errorf("%w: x = %s", ErrShareCommitmentMismatch, share.X)
+	}
+	return nil
+}
+
+// Reconstruct verifies every share before reconstructing the secret, and
+// checks the result against the secret's commitment. The blinding of that
+// commitment is reconstructed from the shares' blinding values.
+func (vss *FeldmanVSS) Reconstruct(shares []Point, blindings []*big.Int, commitments []*big.Int) (*big.Int, error) {
+	if len(blindings) != len(shares) {
+		return nil, fmt.Errorf("%w: %d blinding values for %d shares", ErrShareCommitmentMismatch, len(blindings), len(shares))
+	}
+	blindingShares := make([]Point, len(shares))
+	for i, share := range shares {
+		if err := vss.VerifyShare(share, blindings[i], commitments); err != nil {
+			return nil, err
+		}
+		blindingShares[i] = Point{X: share.X, Y: blindings[i]}
+	}
+
+	prepared, err := vss.sss.prepareShares([][]Point{shares, blindingShares})
+	if err != nil {
+		return nil, err
+	}
+	secret := vss.sss.ReconstructSecret(prepared[0])
+	blinding := vss.sss.ReconstructSecret(prepared[1])
+	if !vss.scheme.Open(commitments[0], secret, blinding) {
+		return nil, fmt.Errorf("%w: reconstructed secret", ErrShareCommitmentMismatch)
+	}
+	return secret, nil
+}
+
+// ErrDealerAbort is returned when the dealer's shares or commitments are
+// inconsistent; participants should abort rather than use any of them
+var ErrDealerAbort = errors.New("dealer sent inconsistent shares or commitments")
+
+// BroadcastVerify checks every share and its blinding value against the
+// commitments, as when all participants broadcast them. It returns the
+// indices into shares of those that do not match, with an ErrDealerAbort
+// error if there are any. With PedersenCommitment it first checks that the
+// commitments themselves lie on one polynomial of the scheme's degree.
+func (vss *FeldmanVSS) BroadcastVerify(shares []Point, blindings []*big.Int, commitments []*big.Int) ([]int, error) {
+	if len(blindings) != len(shares) {
+		return nil, fmt.Errorf("%w: %d blinding values for %d shares", ErrDealerAbort, len(blindings), len(shares))
+	}
+	if err := vss.checkCommitments(commitments); err != nil {
+		return nil, err
+	}
+
+	var bad []int
+	for i, share := range shares {
+		if vss.VerifyShare(share, blindings[i], commitments) != nil {
+			bad = append(bad, i)
+		}
+	}
+	if len(bad) > 0 {
+		return bad, fmt.Errorf("%w: %d of %d shares do not match the commitments", ErrDealerAbort, len(bad), len(shares))
+	}
+	return nil, nil
+}
+
+// DetectDealerCheat lets two participants compare what the dealer sent them.
+// It reports true if their commitment vectors differ, either share does not
+// match its commitments, or the commitments are not consistent.
+func (vss *FeldmanVSS) DetectDealerCheat(ownShare Point, ownBlinding *big.Int, ownCommitments []*big.Int, otherShare Point, otherBlinding *big.Int, otherCommitments []*big.Int) bool {
+	if len(ownCommitments) != len(otherCommitments) {
+		return true
+	}
+	for i := range ownCommitments {
+		if ownCommitments[i].Cmp(otherCommitments[i]) != 0 {
+			return true
+		}
+	}
+
+	return vss.checkCommitments(ownCommitments) != nil ||
+		vss.VerifyShare(ownShare, ownBlinding, ownCommitments) != nil ||
+		vss.VerifyShare(otherShare, otherBlinding, otherCommitments) != nil
+}
+
+// checkCommitments verifies the commitment vector has one entry for the
+// secret and each share. For Pedersen commitments it also checks that they
+// agree with a polynomial of degree threshold-1, by interpolating in the
+// exponent from the first threshold share commitments; the shares and the
+// blinding values both lie on such polynomials, so their commitments do too.
+// Hash commitments have no such structure and are only checked share by
+// share.
+func (vss *FeldmanVSS) checkCommitments(commitments []*big.Int) error {
+	if len(commitments) != vss.sss.numShares+1 {
+		return fmt.Errorf("%w: %d commitments for %d shares", ErrDealerAbort, len(commitments), vss.sss.numShares)
+	}
+
+	pc, ok := vss.scheme.(*PedersenCommitment)
+	if !ok {
+		return nil
+	}
+
+	t := vss.sss.threshold
+	xs := make([]*big.Int, len(commitments))
+	for k := range xs {
+		xs[k] = big.NewInt(int64(k))
+		if k > 0 {
+			xs[k].Add(xs[k], big.NewInt(int64(vss.sss.xOffset)))
+		}
+	}
+
+	// Commitments 1..t determine the polynomial; every other one must agree
+	base := xs[1 : t+1]
+	for k := range commitments {
+		if k >= 1 && k <= t {
+			continue
+		}
+		expected := big.NewInt(1)
+		for i := range base {
+			lambda := lagrangeBasisAt(base, i, xs[k], pc.Q)
+			term := new(big.Int).Exp(commitments[i+1], lambda, pc.P)
+			expected.Mul(expected, term).Mod(expected, pc.P)
+		}
+		if expected.Cmp(commitments[k]) != 0 {
+			return fmt.Errorf("%w: commitment %d is not on the dealer's polynomial", ErrDealerAbort, k)
+		}
+	}
+	return nil
+}
+
+// lagrangeBasisAt evaluates the i-th Lagrange basis polynomial for xs at x
+func lagrangeBasisAt(xs []*big.Int, i int, x, prime *big.Int) *big.Int {
+	numerator := big.NewInt(1)
+	denominator := big.NewInt(1)
+	term := new(big.Int)
+	for j := range xs {
+		if j == i {
+			continue
+		}
+		numerator.Mul(numerator, term.Sub(x, xs[j])).Mod(numerator, prime)
+		denominator.Mul(denominator, term.Sub(xs[i], xs[j])).Mod(denominator, prime)
+	}
+	return numerator.Mul(numerator, modInverse(denominator, prime)).Mod(numerator, prime)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// How often WatchAndShare checks the watched file for changes
+var watchPollInterval = 500 * time.Millisecond
+
+// WatchAndShare shares the contents of filePath whenever the file is
+// written or created, saving the shares to outputBase and passing them to
+// onChange, which may be nil. A file that exists when watching starts is
+// shared straight away. Changes are detected by polling the file's size and
+// modification time, so several writes within one poll interval produce a
+// single re-share. It runs until ctx is cancelled, returning ctx.Err(), or
+// until sharing or saving fails.
+func WatchAndShare(ctx context.Context, filePath, outputBase string, threshold, numShares int, onChange func(newShares [][]Point)) error {
+	sss, err := NewShamirSecretSharing(threshold, numShares)
+	if err != nil {
+		return err
+	}
+
+	var last os.FileInfo
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		info, err := os.Stat(filePath)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			// Rotation may remove the file briefly; share it again once it returns
+			last = nil
+		case err != nil:
+			return err
+		case last == nil || info.Size() != last.Size() || !info.ModTime().Equal(last.ModTime()):
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			allShares, err := sss.ShareArbitraryBytes(data)
+			if err != nil {
+				return err
+			}
+			if err := saveTextSharesMeta(allShares, ShareMetadata{Threshold: threshold, Prime: sss.Prime()}, outputBase); err != nil {
+				return err
+			}
+			last = info
+			if onChange != nil {
+				onChange(allShares)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchAndShare(t *testing.T) {
+	old := watchPollInterval
+	watchPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { watchPollInterval = old })
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "signing.key")
+	outPath := filepath.Join(dir, "signing_shares.txt")
+	if err := os.WriteFile(keyPath, []byte("first key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan [][]Point, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchAndShare(ctx, keyPath, outPath, 2, 3, func(newShares [][]Point) { changes <- newShares })
+	}()
+
+	sss := newTestSharing(t, 2, 3)
+	next := func(want string) {
+		t.Helper()
+		select {
+		case allShares := <-changes:
+			got, err := sss.ReconstructArbitraryBytes(allShares)
+			if err != nil || string(got) != want {
+				t.Fatalf("callback shares reconstruct to %q, %v; want %q", got, err, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no callback for %q", want)
+		}
+	}
+
+	// The existing file is shared straight away
+	next("first key")
+
+	// A rotation with a different size is picked up on the next poll
+	if err := os.WriteFile(keyPath, []byte("rotated signing key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	next("rotated signing key")
+
+	saved, err := loadTextShares(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := sss.ReconstructArbitraryBytes(saved); err != nil || string(got) != "rotated signing key" {
+		t.Fatalf("saved shares reconstruct to %q, %v; want the rotated key", got, err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watcher did not stop after cancellation")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+)
+
+// KeyMetadata describes a private key shared with ShareX509PrivateKey
+type KeyMetadata struct {
+	KeyType   string // e.g. "RSA-2048", "ECDSA-P-256" or "Ed25519"
+	Threshold int
+	NumShares int
+}
+
+// privateKeyType names the algorithm and size of a private key
+func privateKeyType(key crypto.PrivateKey) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return fmt.Sprintf("RSA-%d", k.N.BitLen()), nil
+	case *ecdsa.PrivateKey:
+		return "ECDSA-" + k.Curve.Params().Name, nil
+	case ed25519.PrivateKey:
+		return "Ed25519", nil
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// ShareX509PrivateKey marshals a private key to PKCS#8 DER and shares the bytes.
+// RSA, ECDSA and Ed25519 keys are supported.
+func ShareX509PrivateKey(key crypto.PrivateKey, threshold, numShares int) ([][]Point, KeyMetadata, error) {
+	keyType, err := privateKeyType(key)
+	if err != nil {
+		return nil, KeyMetadata{}, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, KeyMetadata{}, err
+	}
+
+	sss, err := NewShamirSecretSharing(threshold, numShares)
+	if err != nil {
+		return nil, KeyMetadata{}, err
+	}
+	allShares, err := sss.ShareArbitraryBytes(der)
+	if err != nil {
+		return nil, KeyMetadata{}, err
+	}
+
+	meta := KeyMetadata{
+		KeyType:   keyType,
+		Threshold: threshold,
+		NumShares: numShares,
+	}
+	return allShares, meta, nil
+}
+
+// ReconstructX509PrivateKey reconstructs a key shared with ShareX509PrivateKey
+func ReconstructX509PrivateKey(allShares [][]Point, meta KeyMetadata) (crypto.PrivateKey, error) {
+	sss, err := NewShamirSecretSharing(meta.Threshold, meta.NumShares)
+	if err != nil {
+		return nil, err
+	}
+	der, err := sss.ReconstructArbitraryBytes(allShares)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parsing reconstructed key: %w", err)
+	}
+
+	keyType, err := privateKeyType(key)
+	if err != nil {
+		return nil, err
+	}
+	if keyType != meta.KeyType {
+		return nil, fmt.Errorf("reconstructed %s key, expected %s", keyType, meta.KeyType)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestX509PrivateKeyRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := []byte("certificate request")
+	digest := sha256.Sum256(message)
+	for _, tc := range []struct {
+		keyType string
+		key     crypto.Signer
+		verify  func(sig []byte) bool
+	}{
+		{"RSA-2048", rsaKey, func(sig []byte) bool {
+			return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest[:], sig) == nil
+		}},
+		{"ECDSA-P-256", ecKey, func(sig []byte) bool {
+			return ecdsa.VerifyASN1(&ecKey.PublicKey, digest[:], sig)
+		}},
+		{"Ed25519", edKey, func(sig []byte) bool {
+			return ed25519.Verify(edKey.Public().(ed25519.PublicKey), message, sig)
+		}},
+	} {
+		allShares, meta, err := ShareX509PrivateKey(tc.key, 2, 3)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.keyType, err)
+		}
+		if meta.KeyType != tc.keyType {
+			t.Errorf("key type %q, want %q", meta.KeyType, tc.keyType)
+		}
+
+		// Any two holders suffice
+		for i := range allShares {
+			allShares[i] = allShares[i][1:]
+		}
+		key, err := ReconstructX509PrivateKey(allShares, meta)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.keyType, err)
+		}
+
+		signer := key.(crypto.Signer)
+		var sig []byte
+		if tc.keyType == "Ed25519" {
+			sig, err = signer.Sign(rand.Reader, message, crypto.Hash(0))
+		} else {
+			sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+		}
+		if err != nil {
+			t.Fatalf("%s: signing with the reconstructed key: %v", tc.keyType, err)
+		}
+		if !tc.verify(sig) {
+			t.Errorf("%s: signature from the reconstructed key does not verify", tc.keyType)
+		}
+	}
+}
+
+func TestReconstructX509PrivateKeyWrongType(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allShares, meta, err := ShareX509PrivateKey(edKey, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta.KeyType = "RSA-2048"
+	if _, err := ReconstructX509PrivateKey(allShares, meta); err == nil {
+		t.Fatal("reconstructed an Ed25519 key recorded as RSA")
+	}
+}